@@ -0,0 +1,211 @@
+package pkg
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// FailureProbabilities maps each node to its probability of failure, failures are independent between nodes.
+type FailureProbabilities = map[Node]Probability
+
+// Availability describes the probability that at least one quorum has all its nodes alive.
+type Availability struct {
+	Probability float64
+	// Estimated is true when Probability is a Monte Carlo estimate instead of the exact probability, which happens
+	// when the expression has more than 20 repeated nodes that may either fail or survive.
+	Estimated bool
+}
+
+// ReadAvailability returns the probability that at least one read quorum has all its nodes alive, given the
+// independent failure probability of each node.
+func (qs QuorumSystem) ReadAvailability(failureProbabilities FailureProbabilities) (Availability, error) {
+	return availability(qs.reads, failureProbabilities)
+}
+
+// WriteAvailability returns the probability that at least one write quorum has all its nodes alive, given the
+// independent failure probability of each node.
+func (qs QuorumSystem) WriteAvailability(failureProbabilities FailureProbabilities) (Availability, error) {
+	return availability(qs.writes, failureProbabilities)
+}
+
+const (
+	// maxRepeatedNodes is the maximum number of repeated nodes of an Expr whose availability is computed exactly,
+	// the computation enumerates their alive/failed combinations.
+	maxRepeatedNodes = 20
+	// availabilityTrials is the number of trials of the Monte Carlo estimate of the availability beyond
+	// maxRepeatedNodes.
+	availabilityTrials = 20000
+	// availabilitySeed is the seed of the Monte Carlo estimate, so that the same inputs give the same estimate.
+	availabilitySeed = 0
+)
+
+// availability returns the probability that the Expr has at least one quorum alive.
+// For a dup-free Expr the sub-expressions fail independently, and the probability is computed on the structure of
+// the Expr. Otherwise, the result is still exact: the computation is conditioned on every alive/failed combination of
+// the repeated nodes, and once those are fixed the remaining sub-expressions are independent again. The nodes that
+// surely fail or survive are fixed up front. Beyond 20 other repeated nodes, the probability is estimated with a
+// seeded Monte Carlo simulation instead.
+func availability(e Expr, failureProbabilities FailureProbabilities) (Availability, error) {
+	for n := range e.GetNodes() {
+		p, ok := failureProbabilities[n]
+
+		if !ok {
+			return Availability{}, fmt.Errorf("missing failure probability for node %s", n.Name)
+		}
+
+		if p < 0 || p > 1 {
+			return Availability{}, fmt.Errorf("failure probability of node %s must be in [0, 1]", n.Name)
+		}
+	}
+
+	occurrences := make(map[Node]uint)
+	countLeaves(e, occurrences)
+
+	repeated := make([]Node, 0)
+	fixed := make(map[Node]bool)
+
+	for n, count := range occurrences {
+		if count <= 1 {
+			continue
+		}
+
+		switch failureProbabilities[n] {
+		case 0:
+			fixed[n] = true
+		case 1:
+			fixed[n] = false
+		default:
+			repeated = append(repeated, n)
+		}
+	}
+
+	if len(repeated) > maxRepeatedNodes {
+		return Availability{Probability: estimateAvailability(e, failureProbabilities), Estimated: true}, nil
+	}
+
+	total := 0.0
+
+	for mask := 0; mask < 1<<len(repeated); mask++ {
+		weight := 1.0
+
+		for i, n := range repeated {
+			alive := (mask>>i)&1 == 1
+			fixed[n] = alive
+
+			if alive {
+				weight *= 1 - failureProbabilities[n]
+			} else {
+				weight *= failureProbabilities[n]
+			}
+		}
+
+		if weight == 0 {
+			continue
+		}
+
+		total += weight * exprAvailability(e, failureProbabilities, fixed)
+	}
+
+	return Availability{Probability: total}, nil
+}
+
+// estimateAvailability returns the fraction of availabilityTrials seeded trials of independent node failures in
+// which the Expr has at least one quorum alive. The nodes are sampled in a fixed order, so that every call draws the
+// same random numbers: a node failing in a trial also fails there with a higher failure probability.
+func estimateAvailability(e Expr, failureProbabilities FailureProbabilities) float64 {
+	nodes := make([]Node, 0)
+
+	for n := range e.GetNodes() {
+		nodes = append(nodes, n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	rng := rand.New(rand.NewSource(availabilitySeed))
+	model := IndependentFailures{Probabilities: failureProbabilities}
+	alive := 0
+
+	for i := 0; i < availabilityTrials; i++ {
+		failed := model.SampleFailures(nodes, rng)
+		set := make(ExprSet)
+
+		for _, n := range nodes {
+			if !failed[n] {
+				set[n] = true
+			}
+		}
+
+		if e.IsQuorum(set) {
+			alive++
+		}
+	}
+
+	return float64(alive) / availabilityTrials
+}
+
+// exprAvailability returns the probability that the Expr has at least one quorum alive, assuming its
+// sub-expressions are independent once the nodes in fixed are set to alive (true) or failed (false).
+func exprAvailability(e Expr, failureProbabilities FailureProbabilities, fixed map[Node]bool) float64 {
+	switch expr := e.(type) {
+	case Node:
+		if alive, ok := fixed[expr]; ok {
+			if alive {
+				return 1
+			}
+			return 0
+		}
+		return 1 - failureProbabilities[expr]
+	case Or:
+		allFailed := 1.0
+
+		for _, es := range expr.Es {
+			allFailed *= 1 - exprAvailability(es, failureProbabilities, fixed)
+		}
+
+		return 1 - allFailed
+	case And:
+		allAlive := 1.0
+
+		for _, es := range expr.Es {
+			allAlive *= exprAvailability(es, failureProbabilities, fixed)
+		}
+
+		return allAlive
+	case Choose:
+		// alive[j] is the probability that exactly j of the sub-expressions seen so far are alive.
+		alive := make([]float64, len(expr.Es)+1)
+		alive[0] = 1
+
+		for i, es := range expr.Es {
+			p := exprAvailability(es, failureProbabilities, fixed)
+
+			for j := i + 1; j > 0; j-- {
+				alive[j] = alive[j]*(1-p) + alive[j-1]*p
+			}
+			alive[0] *= 1 - p
+		}
+
+		total := 0.0
+
+		for j := expr.K; j < len(alive); j++ {
+			total += alive[j]
+		}
+
+		return total
+	}
+
+	return 0
+}
+
+// countLeaves counts how many times each node appears as a leaf of the Expr.
+func countLeaves(e Expr, occurrences map[Node]uint) {
+	if n, ok := e.(Node); ok {
+		occurrences[n]++
+		return
+	}
+
+	for _, es := range e.GetExprs() {
+		countLeaves(es, occurrences)
+	}
+}
@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestAvailability(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	a, b, c, d, e := NewNode("a"), NewNode("b"), NewNode("c"), NewNode("d"), NewNode("e")
+
+	fp := FailureProbabilities{a: 0.1, b: 0.1, c: 0.1, d: 0.2, e: 0.3}
+
+	// bruteForce enumerates every alive/failed combination of the nodes.
+	bruteForce := func(isQuorum func(set ExprSet) bool, nodes []Node) float64 {
+		total := 0.0
+
+		for mask := 0; mask < 1<<len(nodes); mask++ {
+			alive := make(ExprSet)
+			weight := 1.0
+
+			for i, n := range nodes {
+				if (mask>>i)&1 == 1 {
+					alive[n] = true
+					weight *= 1 - fp[n]
+				} else {
+					weight *= fp[n]
+				}
+			}
+
+			if isQuorum(alive) {
+				total += weight
+			}
+		}
+
+		return total
+	}
+
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	readAvailability, err := qs.ReadAvailability(fp)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(readAvailability.Probability-0.99) <= float64EqualityThreshold,
+		fmt.Sprintf("Actual: %f", readAvailability.Probability))
+
+	writeAvailability, err := qs.WriteAvailability(fp)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(writeAvailability.Probability-0.81) <= float64EqualityThreshold,
+		fmt.Sprintf("Actual: %f", writeAvailability.Probability))
+
+	majority, _ := NewChoose(2, []Expr{a, b, c})
+	qs = NewQuorumSystemWithReads(majority)
+
+	readAvailability, err = qs.ReadAvailability(fp)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(readAvailability.Probability-0.972) <= float64EqualityThreshold,
+		fmt.Sprintf("Actual: %f", readAvailability.Probability))
+
+	// The same majority written as a non dup-free expression.
+	qs = NewQuorumSystemWithReads((a.Multiply(b)).Add(b.Multiply(c)).Add(a.Multiply(c)))
+
+	readAvailability, err = qs.ReadAvailability(fp)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(readAvailability.Probability-0.972) <= float64EqualityThreshold,
+		fmt.Sprintf("Actual: %f", readAvailability.Probability))
+
+	choose, _ := NewChoose(2, []Expr{a.Multiply(b), c.Add(d), e, a.Add(e)})
+
+	exprs := []Expr{
+		(a.Add(b)).Multiply(c.Add(d)).Add(e),
+		(a.Multiply(b)).Add(b.Multiply(c)).Add(a.Multiply(d)).Add(a.Multiply(d).Multiply(e)),
+		choose,
+	}
+
+	for _, expr := range exprs {
+		qs = NewQuorumSystemWithReads(expr)
+		nodes := qs.GetNodesAsArray()
+
+		readAvailability, err = qs.ReadAvailability(fp)
+		assert.NilError(t, err)
+		assert.Assert(t,
+			math.Abs(readAvailability.Probability-bruteForce(qs.IsReadQuorum, nodes)) <= float64EqualityThreshold)
+
+		writeAvailability, err = qs.WriteAvailability(fp)
+		assert.NilError(t, err)
+		assert.Assert(t,
+			math.Abs(writeAvailability.Probability-bruteForce(qs.IsWriteQuorum, nodes)) <= float64EqualityThreshold)
+	}
+
+	// Each node is repeated: the nodes that surely fail or survive are fixed, the others are enumerated.
+	nodes := make([]Expr, 0, maxRepeatedNodes+1)
+	fp = make(FailureProbabilities)
+
+	for i := 0; i <= maxRepeatedNodes; i++ {
+		n := NewNode(fmt.Sprintf("n%d", i))
+		nodes = append(nodes, n)
+		fp[n] = 0
+	}
+
+	qs = NewQuorumSystemWithReads(Or{Es: []Expr{And{Es: nodes}, Or{Es: nodes}}})
+
+	readAvailability, err = qs.ReadAvailability(fp)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(readAvailability.Probability-1) <= float64EqualityThreshold)
+	assert.Assert(t, !readAvailability.Estimated)
+
+	// Beyond 20 repeated nodes the availability is estimated: the expression is available when any node is alive.
+	for _, n := range nodes {
+		fp[n.(Node)] = 0.7
+	}
+
+	readAvailability, err = qs.ReadAvailability(fp)
+	assert.NilError(t, err)
+	assert.Assert(t, readAvailability.Estimated)
+
+	exact := 1 - math.Pow(0.7, maxRepeatedNodes+1)
+	assert.Assert(t, math.Abs(readAvailability.Probability-exact) <= 0.005,
+		fmt.Sprintf("Exact: %f | Estimate: %f", exact, readAvailability.Probability))
+
+	again, err := qs.ReadAvailability(fp)
+	assert.NilError(t, err)
+	assert.Equal(t, again, readAvailability)
+
+	_, err = NewQuorumSystemWithReads(a.Add(b)).ReadAvailability(FailureProbabilities{a: 0.1})
+	assert.Error(t, err, "missing failure probability for node b")
+
+	_, err = NewQuorumSystemWithReads(a.Add(b)).ReadAvailability(FailureProbabilities{a: 0.1, b: 1.5})
+	assert.Error(t, err, "failure probability of node b must be in [0, 1]")
+}
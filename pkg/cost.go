@@ -0,0 +1,51 @@
+package pkg
+
+// Cost calculates and returns the expected monetary cost of an operation of the strategy given a read and write
+// Distribution: a read costs the cost per read of every node of its quorum, a write the cost per write.
+func (s Strategy) Cost(rf *Distribution, wf *Distribution) (float64, error) {
+	d, err := canonicalizeReadsWrites(rf, wf)
+
+	if err != nil {
+		return -1, err
+	}
+
+	fr := 0.0
+
+	for f, p := range d {
+		fr += p * f
+	}
+
+	cost := 0.0
+
+	for _, sigma := range s.SigmaR.Values {
+		cost += fr * sigma.Probability * s.Qs.readQuorumCost(sigma.Quorum)
+	}
+
+	for _, sigma := range s.SigmaW.Values {
+		cost += (1 - fr) * sigma.Probability * s.Qs.writeQuorumCost(sigma.Quorum)
+	}
+
+	return cost, nil
+}
+
+// readQuorumCost returns the cost of a read on the quorum, the sum of the cost per read of its nodes.
+func (qs QuorumSystem) readQuorumCost(quorum ExprSet) float64 {
+	cost := 0.0
+
+	for _, n := range quorumNodes(qs, quorum) {
+		cost += n.GetReadCost()
+	}
+
+	return cost
+}
+
+// writeQuorumCost returns the cost of a write on the quorum, the sum of the cost per write of its nodes.
+func (qs QuorumSystem) writeQuorumCost(quorum ExprSet) float64 {
+	cost := 0.0
+
+	for _, n := range quorumNodes(qs, quorum) {
+		cost += n.GetWriteCost()
+	}
+
+	return cost
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestCost(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b := NewNode("a").WithCosts(1, 2), NewNode("b").WithCosts(5, 10)
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	strategyOptions := StrategyOptions{
+		Optimize: Cost,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}
+
+	// The reads go to the cheapest node.
+	strategy, err := qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	cost, err := strategy.Cost(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(cost-1) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", cost))
+
+	// The load optimal strategy balances the reads: 0.5 * 1 + 0.5 * 5.
+	strategyOptions.Optimize = Load
+
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	cost, err = strategy.Cost(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(cost-3) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", cost))
+
+	// A cost of 2 sends at least 3/4 of the reads to a.
+	costLimit := 2.0
+	strategyOptions.CostLimit = &costLimit
+
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	load, err := strategy.Load(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-0.75) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", load))
+
+	// A write goes to both nodes.
+	var rf Distribution = QuorumDistribution{values: DistributionValues{0: 1}}
+	var wf Distribution
+
+	cost, err = strategy.Cost(&rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(cost-12) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", cost))
+
+	strategyOptions.Optimize = Cost
+	_, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.Error(t, err, "a cost limit cannot be set when optimizing for cost")
+
+	_, err = NewQuorumSystemWithReads(a.Add(NewNode("c").WithCosts(-1, 0))).Strategy(
+		initializeStrategyOptions(StrategyOptions{Optimize: Cost}))
+	assert.Error(t, err, "the costs of node c must be >= 0")
+
+	// The frontier trades the load for the cost.
+	strategyOptions.CostLimit = nil
+	frontier, err := qs.ParetoFrontier([]OptimizeType{Load, Cost}, 5, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, len(frontier) == 5, fmt.Sprintf("Actual:%d", len(frontier)))
+	assert.Assert(t, math.Abs(frontier[0].Load-0.5) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(frontier[0].Cost-3) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(frontier[4].Load-1) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(frontier[4].Cost-1) < float64EqualityThreshold)
+}
@@ -1,6 +1,9 @@
 package pkg
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type Fraction = float64
 type Weight = float64
@@ -97,3 +100,150 @@ func canonicalize(d *Distribution) (map[Fraction]Probability, error) {
 
 	return result, nil
 }
+
+// NewPointDistribution returns the Distribution where the read fraction is always fr.
+func NewPointDistribution(fr Fraction) (QuorumDistribution, error) {
+	return NewHistogramDistribution(DistributionValues{fr: 1})
+}
+
+// NewUniformDistribution returns the uniform Distribution over [lo, hi], discretized into n read fractions evenly
+// spaced between lo and hi, both included. With n = 1, the read fraction is the middle of the range.
+func NewUniformDistribution(lo Fraction, hi Fraction, n uint) (QuorumDistribution, error) {
+	if n == 0 {
+		return QuorumDistribution{}, fmt.Errorf("n must be >= 1")
+	}
+
+	if lo > hi {
+		return QuorumDistribution{}, fmt.Errorf("lo must be <= hi")
+	}
+
+	if n == 1 {
+		return NewPointDistribution((lo + hi) / 2)
+	}
+
+	values := make(DistributionValues)
+
+	for _, f := range FractionGrid(n) {
+		values[lo+(hi-lo)*f] += 1
+	}
+
+	return NewHistogramDistribution(values)
+}
+
+// NewHistogramDistribution returns the Distribution given the weight of each read fraction, weights are normalized.
+func NewHistogramDistribution(values DistributionValues) (QuorumDistribution, error) {
+	if len(values) == 0 {
+		return QuorumDistribution{}, fmt.Errorf("distribution cannot be nil")
+	}
+
+	result := make(DistributionValues)
+	totalWeight := 0.0
+
+	for f, w := range values {
+		if f < 0 || f > 1 {
+			return QuorumDistribution{}, fmt.Errorf("read fraction %v must be in [0, 1]", f)
+		}
+
+		if w < 0 {
+			return QuorumDistribution{}, fmt.Errorf("distribution cannot have negative weights")
+		}
+
+		totalWeight += w
+		result[f] = w
+	}
+
+	if totalWeight == 0 {
+		return QuorumDistribution{}, fmt.Errorf("distribution cannot have zero weight")
+	}
+
+	return QuorumDistribution{values: result}, nil
+}
+
+// NewBetaDistribution returns the Beta(alpha, beta) Distribution discretized into n read fractions: [0, 1] is split
+// into n intervals of the same width, and the middle of each interval weighs the probability of the interval.
+func NewBetaDistribution(alpha float64, beta float64, n uint) (QuorumDistribution, error) {
+	if alpha <= 0 || beta <= 0 {
+		return QuorumDistribution{}, fmt.Errorf("alpha and beta must be > 0")
+	}
+
+	if n == 0 {
+		return QuorumDistribution{}, fmt.Errorf("n must be >= 1")
+	}
+
+	values := make(DistributionValues)
+	previous := 0.0
+
+	for i := uint(1); i <= n; i++ {
+		cdf := regularizedIncompleteBeta(float64(i)/float64(n), alpha, beta)
+		values[(float64(i)-0.5)/float64(n)] = math.Max(cdf-previous, 0)
+		previous = cdf
+	}
+
+	return NewHistogramDistribution(values)
+}
+
+// regularizedIncompleteBeta returns I_x(a, b), the CDF of the Beta(a, b) distribution at x, evaluated with its
+// continued fraction.
+func regularizedIncompleteBeta(x float64, a float64, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	if x >= 1 {
+		return 1
+	}
+
+	// The continued fraction converges quickly for x < (a + 1) / (a + b + 2), the symmetry
+	// I_x(a, b) = 1 - I_1-x(b, a) covers the other values.
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedIncompleteBeta(1-x, b, a)
+	}
+
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	// Modified Lentz's method.
+	const tiny = 1e-300
+	const epsilon = 1e-15
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+
+	d = 1 / d
+	f := d
+
+	for m := 1; m <= 1000; m++ {
+		fm := float64(m)
+
+		for _, numerator := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + numerator*d
+
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+
+			c = 1 + numerator/c
+
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+
+			d = 1 / d
+			f *= c * d
+		}
+
+		if math.Abs(c*d-1) < epsilon {
+			break
+		}
+	}
+
+	return front * f / a
+}
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestDistributionConstructors(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	point, err := NewPointDistribution(0.25)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, point.GetValue(), DistributionValues{0.25: 1})
+
+	uniform, err := NewUniformDistribution(0.2, 0.6, 3)
+	assert.NilError(t, err)
+	assert.Equal(t, len(uniform.GetValue()), 3)
+
+	for _, fr := range []Fraction{0.2, 0.4, 0.6} {
+		found := false
+
+		for f, w := range uniform.GetValue() {
+			if math.Abs(f-fr) < float64EqualityThreshold {
+				found = true
+				assert.Equal(t, w, 1.0)
+			}
+		}
+
+		assert.Assert(t, found, fmt.Sprintf("Missing:%f", fr))
+	}
+
+	uniform, err = NewUniformDistribution(0.2, 0.6, 1)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, uniform.GetValue(), DistributionValues{0.4: 1})
+
+	histogram, err := NewHistogramDistribution(DistributionValues{0: 1, 1: 3})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, histogram.GetValue(), DistributionValues{0: 1, 1: 3})
+
+	// Beta(1, 1) is uniform, Beta(2, 2) puts 0.15625 below 0.25.
+	beta, err := NewBetaDistribution(1, 1, 4)
+	assert.NilError(t, err)
+
+	for f, w := range beta.GetValue() {
+		assert.Assert(t, math.Abs(w-0.25) < float64EqualityThreshold, fmt.Sprintf("%f: %f", f, w))
+	}
+
+	beta, err = NewBetaDistribution(2, 2, 4)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(beta.GetValue()[0.125]-0.15625) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(beta.GetValue()[0.375]-0.34375) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(beta.GetValue()[0.875]-0.15625) < float64EqualityThreshold)
+
+	beta, err = NewBetaDistribution(0.5, 3, 10)
+	assert.NilError(t, err)
+
+	total := 0.0
+
+	for _, w := range beta.GetValue() {
+		total += w
+	}
+
+	assert.Assert(t, math.Abs(total-1) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", total))
+
+	_, err = NewPointDistribution(1.5)
+	assert.Error(t, err, "read fraction 1.5 must be in [0, 1]")
+
+	_, err = NewHistogramDistribution(DistributionValues{0.5: -1})
+	assert.Error(t, err, "distribution cannot have negative weights")
+
+	_, err = NewHistogramDistribution(DistributionValues{0.5: 0})
+	assert.Error(t, err, "distribution cannot have zero weight")
+
+	_, err = NewUniformDistribution(0, 1, 0)
+	assert.Error(t, err, "n must be >= 1")
+
+	_, err = NewUniformDistribution(0.6, 0.2, 2)
+	assert.Error(t, err, "lo must be <= hi")
+
+	_, err = NewBetaDistribution(0, 1, 2)
+	assert.Error(t, err, "alpha and beta must be > 0")
+}
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DomainResilience returns the number of whole failure domains at the given level (e.g. "zone") that can fail while
+// both a read quorum and a write quorum remain available - min(readDomainResilience, writeDomainResilience).
+func (qs QuorumSystem) DomainResilience(level string) (uint, error) {
+	rres, err := qs.ReadDomainResilience(level)
+
+	if err != nil {
+		return 0, err
+	}
+
+	wres, err := qs.WriteDomainResilience(level)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if rres < wres {
+		return rres, nil
+	}
+
+	return wres, nil
+}
+
+// ReadDomainResilience returns the number of whole failure domains at the given level that can fail while a read
+// quorum remains available.
+func (qs QuorumSystem) ReadDomainResilience(level string) (uint, error) {
+	domains, err := qs.minDomainHittingSet(qs.ListReadQuorums(), level)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(len(domains)) - 1, nil
+}
+
+// WriteDomainResilience returns the number of whole failure domains at the given level that can fail while a write
+// quorum remains available.
+func (qs QuorumSystem) WriteDomainResilience(level string) (uint, error) {
+	domains, err := qs.minDomainHittingSet(qs.ListWriteQuorums(), level)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(len(domains)) - 1, nil
+}
+
+// SmallestDomainFailureSet returns one of the smallest sets of failure domains at the given level whose failure leaves
+// either no read quorum or no write quorum available. Its size is DomainResilience(level) + 1.
+func (qs QuorumSystem) SmallestDomainFailureSet(level string) ([]string, error) {
+	readDomains, err := qs.minDomainHittingSet(qs.ListReadQuorums(), level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	writeDomains, err := qs.minDomainHittingSet(qs.ListWriteQuorums(), level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(writeDomains) < len(readDomains) {
+		return writeDomains, nil
+	}
+
+	return readDomains, nil
+}
+
+// nodeDomains returns the failure domain at the given level of every node in the quorum system.
+func (qs QuorumSystem) nodeDomains(level string) (map[Node]string, error) {
+	result := make(map[Node]string)
+
+	for n := range qs.GetNodes() {
+		domain, ok := n.Domain(level)
+
+		if !ok {
+			return nil, fmt.Errorf("node %s has no failure domain at level %s", n.Name, level)
+		}
+
+		result[n] = domain
+	}
+
+	return result, nil
+}
+
+// minDomainHittingSet returns the sorted names of the smallest set of failure domains at the given level that
+// intersects every quorum.
+func (qs QuorumSystem) minDomainHittingSet(quorums []ExprSet, level string) ([]string, error) {
+	nodeToDomain, err := qs.nodeDomains(level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(quorums) == 0 {
+		return nil, fmt.Errorf("no quorums to fail")
+	}
+
+	domains := make([]string, 0)
+	domainToIndex := make(map[string]int)
+	sets := make([][]int, 0, len(quorums))
+
+	for _, q := range quorums {
+		seen := make(map[int]bool)
+		set := make([]int, 0)
+
+		for x := range q {
+			domain := nodeToDomain[x.(Node)]
+
+			if _, exists := domainToIndex[domain]; !exists {
+				domainToIndex[domain] = len(domains)
+				domains = append(domains, domain)
+			}
+
+			if !seen[domainToIndex[domain]] {
+				seen[domainToIndex[domain]] = true
+				set = append(set, domainToIndex[domain])
+			}
+		}
+
+		sort.Ints(set)
+		sets = append(sets, set)
+	}
+
+	result := make([]string, 0)
+
+	for _, k := range minimumHittingSetIndices(sets, len(domains)) {
+		result = append(result, domains[k])
+	}
+
+	sort.Strings(result)
+
+	return result, nil
+}
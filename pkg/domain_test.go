@@ -0,0 +1,152 @@
+package pkg
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestDomainResilience(t *testing.T) {
+	a1, a2 := NewNode("a1").WithDomains(FailureDomains{"zone": "a", "region": "eu"}),
+		NewNode("a2").WithDomains(FailureDomains{"zone": "a", "region": "eu"})
+	b1, b2 := NewNode("b1").WithDomains(FailureDomains{"zone": "b", "region": "eu"}),
+		NewNode("b2").WithDomains(FailureDomains{"zone": "b", "region": "eu"})
+	c1, c2 := NewNode("c1").WithDomains(FailureDomains{"zone": "c", "region": "us"}),
+		NewNode("c2").WithDomains(FailureDomains{"zone": "c", "region": "us"})
+
+	nodes := []Expr{a1, a2, b1, b2, c1, c2}
+
+	zone, ok := a1.Domain("zone")
+	assert.Assert(t, ok)
+	assert.Equal(t, zone, "a")
+
+	_, ok = NewNode("x").Domain("zone")
+	assert.Assert(t, !ok)
+
+	// 4 out of 6: any zone can fail, there are still 4 nodes for reads and 3 for writes.
+	choose, _ := NewChoose(4, nodes)
+	qs := NewQuorumSystemWithReads(choose)
+
+	assert.Equal(t, qs.Resilience(), uint(2))
+
+	resilience, err := qs.DomainResilience("zone")
+	assert.NilError(t, err)
+	assert.Equal(t, resilience, uint(1))
+
+	// Losing the eu region leaves 2 nodes only.
+	resilience, err = qs.DomainResilience("region")
+	assert.NilError(t, err)
+	assert.Equal(t, resilience, uint(0))
+
+	domains, err := qs.SmallestDomainFailureSet("region")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, domains, []string{"eu"})
+
+	// 5 out of 6: a single zone failure takes out the reads.
+	choose, _ = NewChoose(5, nodes)
+	qs = NewQuorumSystemWithReads(choose)
+
+	assert.Equal(t, qs.Resilience(), uint(1))
+
+	resilience, err = qs.ReadDomainResilience("zone")
+	assert.NilError(t, err)
+	assert.Equal(t, resilience, uint(0))
+
+	resilience, err = qs.WriteDomainResilience("zone")
+	assert.NilError(t, err)
+	assert.Equal(t, resilience, uint(2))
+
+	// One node per zone in each read quorum.
+	qs = NewQuorumSystemWithReads((a1.Multiply(b1)).Add(b2.Multiply(c1)).Add(c2.Multiply(a2)))
+
+	resilience, err = qs.ReadDomainResilience("zone")
+	assert.NilError(t, err)
+	assert.Equal(t, resilience, uint(1))
+
+	_, err = NewQuorumSystemWithReads(a1.Add(NewNode("x"))).DomainResilience("zone")
+	assert.Error(t, err, "node x has no failure domain at level zone")
+}
+
+func TestDomainStrategy(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	a1, a2 := NewNode("a1").WithDomains(FailureDomains{"zone": "a"}), NewNode("a2").WithDomains(FailureDomains{"zone": "a"})
+	b1, b2 := NewNode("b1").WithDomains(FailureDomains{"zone": "b"}), NewNode("b2").WithDomains(FailureDomains{"zone": "b"})
+	c1, c2 := NewNode("c1").WithDomains(FailureDomains{"zone": "c"}), NewNode("c2").WithDomains(FailureDomains{"zone": "c"})
+
+	choose, _ := NewChoose(4, []Expr{a1, a2, b1, b2, c1, c2})
+	qs := NewQuorumSystemWithReads(choose)
+
+	zones := func(set ExprSet) map[string]bool {
+		result := make(map[string]bool)
+
+		for x := range set {
+			zone, _ := x.(Node).Domain("zone")
+			result[zone] = true
+		}
+
+		return result
+	}
+
+	strategyOptions := StrategyOptions{
+		Optimize:     Load,
+		ReadFraction: QuorumDistribution{values: DistributionValues{1: 1}},
+	}
+
+	load, err := qs.Load(strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-4.0/6) <= float64EqualityThreshold)
+
+	// Only the read quorum made of all the nodes survives the failure of any zone.
+	strategyOptions.DomainLevel = "zone"
+	strategyOptions.D = 1
+
+	strategy, err := qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	for _, sigma := range strategy.SigmaR.Values {
+		if sigma.Probability > float64EqualityThreshold {
+			assert.Equal(t, len(sigma.Quorum), 6)
+		}
+	}
+
+	load, err = strategy.Load(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-1) <= float64EqualityThreshold)
+
+	strategyOptions.D = 0
+	strategyOptions.MinDomains = 3
+
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	for _, sigma := range append(strategy.SigmaR.Values, strategy.SigmaW.Values...) {
+		assert.Equal(t, len(zones(sigma.Quorum)), 3)
+	}
+
+	strategyOptions.MinDomains = 4
+
+	_, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.Error(t, err, "there are no quorums that survive 0 zone failures spanning at least 4 domains")
+
+	strategyOptions.DomainLevel = ""
+
+	_, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.Error(t, err, "a failure domain level must be set when D or MinDomains are set")
+
+	x, y, z := NewNode("x").WithDomains(FailureDomains{"zone": "a"}),
+		NewNode("y").WithDomains(FailureDomains{"zone": "b"}),
+		NewNode("z").WithDomains(FailureDomains{"zone": "c"})
+
+	sr, err := Search(SearchOptions{
+		Optimize:     Load,
+		ReadFraction: QuorumDistribution{values: DistributionValues{0.5: 1}},
+		DomainLevel:  "zone",
+		D:            1,
+	}, x, y, z)
+	assert.NilError(t, err)
+
+	for _, sigma := range append(sr.Strategy.SigmaR.Values, sr.Strategy.SigmaW.Values...) {
+		assert.Assert(t, len(zones(sigma.Quorum)) >= 2)
+	}
+}
@@ -1,25 +1,32 @@
-package pkg
+package pkg_test
 
 import (
 	"fmt"
+	"github.com/samueleresca/quoracle-go/pkg"
 	"testing"
+	"time"
 )
 
 func TestStrategyUseCase(t *testing.T) {
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1),
-		NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3),
-		NewNodeWithCapacityAndLatency("d", 2, 1, 4)
+		pkg.NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond),
+		pkg.NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		pkg.NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond),
+		pkg.NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
 
 	// Read quorum (a*b) + (c*d)
-	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
+	qs := pkg.NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
 
 	// Load optimized strategy with read_fraction 100%
-	strategyOptions := StrategyOptions{
-		Optimize: Load,
-		ReadFraction: QuorumDistribution{
-			values: DistributionValues{1: 1}},
+	readFraction, err := pkg.NewPointDistribution(1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strategyOptions := pkg.StrategyOptions{
+		Optimize:     pkg.Load,
+		ReadFraction: readFraction,
 	}
 
 	load, _ := qs.Load(strategyOptions)
@@ -33,17 +40,23 @@ func TestStrategyUseCase(t *testing.T) {
 
 func TestSearchUseCase(t *testing.T) {
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1),
-		NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3),
-		NewNodeWithCapacityAndLatency("d", 2, 1, 4)
-
-	so := SearchOptions{
-		Optimize:     Load,
-		ReadFraction: QuorumDistribution{DistributionValues{0.5: 1.0}},
+		pkg.NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond),
+		pkg.NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		pkg.NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond),
+		pkg.NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
+
+	readFraction, err := pkg.NewPointDistribution(0.5)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	so := pkg.SearchOptions{
+		Optimize:     pkg.Load,
+		ReadFraction: readFraction,
 	}
 
-	sr, err := Search(so, a, b, c, d)
+	sr, err := pkg.Search(so, a, b, c, d)
 
 	if err != nil {
 		fmt.Println(err.Error())
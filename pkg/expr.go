@@ -2,12 +2,11 @@ package pkg
 
 import (
 	"fmt"
-	"github.com/lanl/clp"
-	"math"
 	"math/bits"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 )
 
 // ExprSet describes a set of Expr.
@@ -91,12 +90,37 @@ type Expr interface {
 	fmt.Stringer
 }
 
-// Node represents a node in an Expr.
+// FailureDomains maps a failure domain level (e.g. "rack", "zone", "region") to the domain the node belongs to at
+// that level (e.g. "us-east-1a").
+type FailureDomains = map[string]string
+
+const (
+	// DefaultCapacity is the read and write capacity of a node without capacities.
+	DefaultCapacity = 1.0
+	// DefaultLatency is the latency of a node without latency.
+	DefaultLatency = time.Second
+	// DefaultLinkCost is the link cost of a node without link cost.
+	DefaultLinkCost = 1.0
+)
+
+// Node represents a node in an Expr. Capacities and latency are optional, a nil value stands for DefaultCapacity
+// or DefaultLatency.
 type Node struct {
 	Name          string
-	ReadCapacity  *uint
-	WriteCapacity *uint
-	Latency       *uint
+	ReadCapacity  *float64
+	WriteCapacity *float64
+	Latency       *time.Duration
+	Domains       *FailureDomains
+	// LatencyDistribution optionally defines the distribution of the latency of the node, used by the latency
+	// percentiles. Without it, the latency of the node is always Latency.
+	LatencyDistribution LatencyDistribution
+	// LinkCost optionally defines the cost of sending a unit of payload to the node (e.g. the cross-region egress
+	// price), used by the network load. A nil value stands for DefaultLinkCost.
+	LinkCost *float64
+	// ReadCost and WriteCost optionally define the monetary cost of a read and a write served by the node (e.g. the
+	// instance and egress price per request), used by the cost. A nil value stands for 0.
+	ReadCost  *float64
+	WriteCost *float64
 }
 
 // NewNode define a new node with a name.
@@ -104,7 +128,7 @@ func NewNode(name string) Node {
 	node := Node{}
 	node.Name = name
 
-	initialValue := uint(1)
+	initialValue := DefaultCapacity
 	node.ReadCapacity = &initialValue
 	node.WriteCapacity = &initialValue
 
@@ -112,7 +136,7 @@ func NewNode(name string) Node {
 }
 
 // NewNodeWithCapacityAndLatency defines a new node with a name, read and write capacities and a latency.
-func NewNodeWithCapacityAndLatency(name string, readCapacity uint, writeCapacity uint, latency uint) Node {
+func NewNodeWithCapacityAndLatency(name string, readCapacity float64, writeCapacity float64, latency time.Duration) Node {
 	node := Node{}
 
 	node.Name = name
@@ -124,7 +148,7 @@ func NewNodeWithCapacityAndLatency(name string, readCapacity uint, writeCapacity
 }
 
 // NewNodeWithCapacity defines a new node with a name a read and write capacity.
-func NewNodeWithCapacity(name string, readCapacity uint, writeCapacity uint) Node {
+func NewNodeWithCapacity(name string, readCapacity float64, writeCapacity float64) Node {
 	node := Node{}
 
 	node.Name = name
@@ -135,11 +159,11 @@ func NewNodeWithCapacity(name string, readCapacity uint, writeCapacity uint) Nod
 }
 
 // NewNodeWithLatency defines a new node with a name and a latency.
-func NewNodeWithLatency(name string, latency uint) Node {
+func NewNodeWithLatency(name string, latency time.Duration) Node {
 	node := Node{}
 
 	node.Name = name
-	initialValue := uint(1)
+	initialValue := DefaultCapacity
 	node.ReadCapacity = &initialValue
 	node.WriteCapacity = &initialValue
 	node.Latency = &latency
@@ -147,6 +171,126 @@ func NewNodeWithLatency(name string, latency uint) Node {
 	return node
 }
 
+// GetReadCapacity returns the read capacity of the node, DefaultCapacity when it is not set.
+func (n Node) GetReadCapacity() float64 {
+	if n.ReadCapacity == nil {
+		return DefaultCapacity
+	}
+
+	return *n.ReadCapacity
+}
+
+// GetWriteCapacity returns the write capacity of the node, DefaultCapacity when it is not set.
+func (n Node) GetWriteCapacity() float64 {
+	if n.WriteCapacity == nil {
+		return DefaultCapacity
+	}
+
+	return *n.WriteCapacity
+}
+
+// GetLatency returns the latency of the node. When it is not set, it is the mean of the LatencyDistribution of the
+// node, or DefaultLatency.
+func (n Node) GetLatency() time.Duration {
+	if n.Latency != nil {
+		return *n.Latency
+	}
+
+	if n.LatencyDistribution != nil {
+		return n.LatencyDistribution.Mean()
+	}
+
+	return DefaultLatency
+}
+
+// GetLatencyDistribution returns the distribution of the latency of the node. When it is not set, the latency of the
+// node is always GetLatency.
+func (n Node) GetLatencyDistribution() LatencyDistribution {
+	if n.LatencyDistribution != nil {
+		return n.LatencyDistribution
+	}
+
+	return ConstantLatency{Latency: n.GetLatency()}
+}
+
+// WithLatencyDistribution returns a copy of the node with the given latency distribution.
+func (n Node) WithLatencyDistribution(distribution LatencyDistribution) Node {
+	n.LatencyDistribution = distribution
+
+	return n
+}
+
+// GetLinkCost returns the link cost of the node, DefaultLinkCost when it is not set.
+func (n Node) GetLinkCost() float64 {
+	if n.LinkCost == nil {
+		return DefaultLinkCost
+	}
+
+	return *n.LinkCost
+}
+
+// WithLinkCost returns a copy of the node with the given link cost.
+func (n Node) WithLinkCost(cost float64) Node {
+	n.LinkCost = &cost
+
+	return n
+}
+
+// GetReadCost returns the cost of a read served by the node, 0 when it is not set.
+func (n Node) GetReadCost() float64 {
+	if n.ReadCost == nil {
+		return 0
+	}
+
+	return *n.ReadCost
+}
+
+// GetWriteCost returns the cost of a write served by the node, 0 when it is not set.
+func (n Node) GetWriteCost() float64 {
+	if n.WriteCost == nil {
+		return 0
+	}
+
+	return *n.WriteCost
+}
+
+// WithCosts returns a copy of the node with the given cost per read and cost per write.
+func (n Node) WithCosts(readCost float64, writeCost float64) Node {
+	n.ReadCost = &readCost
+	n.WriteCost = &writeCost
+
+	return n
+}
+
+// milliseconds returns the duration in milliseconds, the unit of the latency metrics and limits.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WithDomains returns a copy of the node labelled with the given failure domains.
+func (n Node) WithDomains(domains FailureDomains) Node {
+	labels := make(FailureDomains)
+
+	for level, domain := range domains {
+		labels[level] = domain
+	}
+
+	n.Domains = &labels
+
+	return n
+}
+
+// Domain returns the failure domain of the node at the given level, if any.
+func (n Node) Domain(level string) (string, bool) {
+	if n.Domains == nil {
+		return "", false
+	}
+
+	domain, ok := (*n.Domains)[level]
+
+	return domain, ok
+}
+
 func (n Node) Add(expr Expr) Or {
 	return mergeWithOr(n, expr)
 }
@@ -193,13 +337,7 @@ func (n Node) Resilience() uint {
 		return n.MinFailures() - 1
 	}
 
-	qs := make([]ExprSet, 0)
-
-	for q := range n.Quorums() {
-		qs = append(qs, q)
-	}
-
-	return minHittingSet(qs) - 1.0
+	return hittingSetResilience(n)
 }
 
 func (n Node) DupFree() bool {
@@ -296,13 +434,7 @@ func (e Or) Resilience() uint {
 		return e.MinFailures() - 1
 	}
 
-	qs := make([]ExprSet, 0)
-
-	for q := range e.Quorums() {
-		qs = append(qs, q)
-	}
-
-	return minHittingSet(qs) - 1.0
+	return hittingSetResilience(e)
 }
 
 func (e Or) DupFree() bool {
@@ -434,13 +566,7 @@ func (e And) Resilience() uint {
 		return e.MinFailures() - 1
 	}
 
-	qs := make([]ExprSet, 0)
-
-	for q := range e.Quorums() {
-		qs = append(qs, q)
-	}
-
-	return minHittingSet(qs) - 1
+	return hittingSetResilience(e)
 }
 
 func (e And) DupFree() bool {
@@ -611,13 +737,7 @@ func (e Choose) Resilience() uint {
 		return e.MinFailures() - 1
 	}
 
-	qs := make([]ExprSet, 0)
-
-	for q := range e.Quorums() {
-		qs = append(qs, q)
-	}
-
-	return minHittingSet(qs) - 1.0
+	return hittingSetResilience(e)
 }
 
 func (e Choose) DupFree() bool {
@@ -757,74 +877,196 @@ func setToArr(input ExprSet) []Expr {
 	return result
 }
 
-func minHittingSet(quorums []ExprSet) uint {
+// hittingSetResilience returns the resilience of an Expr that is not duplicate free, computed as the size of
+// the minimum hitting set of its quorums minus one. It panics if a quorum is empty, which no Expr can produce.
+func hittingSetResilience(e Quorum) uint {
+	qs := make([]ExprSet, 0)
 
-	keys := make([]Expr, 0)
+	for q := range e.Quorums() {
+		qs = append(qs, q)
+	}
+
+	size, err := minHittingSet(qs)
+
+	// minHittingSet only fails on an empty quorum, and the quorums of an Expr always contain at least one node: an
+	// error is a bug, not a resilience of 0.
+	if err != nil {
+		panic(fmt.Sprintf("the quorums of %v cannot be hit: %v", e, err))
+	}
+
+	if size == 0 {
+		return 0
+	}
 
-	def := lpDefinition{}
-	def.Vars = make([]float64, 0)
-	def.Constraints = make([][2]float64, 0)
-	def.Objectives = make([][]float64, 0)
+	return size - 1
+}
 
-	simp := clp.NewSimplex()
+// minHittingSet returns the size of the minimum hitting set of a list of quorums, i.e. the smallest set of Expr
+// that intersects every quorum.
+func minHittingSet(quorums []ExprSet) (uint, error) {
+	set, err := minimumHittingSet(quorums)
 
-	uniqueKeys := make(map[Expr]float64)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(len(set)), nil
+}
+
+// minimumHittingSet returns a minimum hitting set of a list of quorums.
+func minimumHittingSet(quorums []ExprSet) (ExprSet, error) {
+	if len(quorums) == 0 {
+		return ExprSet{}, nil
+	}
+
+	keys := make([]Expr, 0)
+	keyToIndex := make(map[Expr]int)
+	sets := make([][]int, 0, len(quorums))
 
 	for _, xs := range quorums {
+		if len(xs) == 0 {
+			return nil, fmt.Errorf("an empty quorum cannot be hit")
+		}
+
+		set := make([]int, 0, len(xs))
+
 		for k := range xs {
-			if _, exists := uniqueKeys[k]; !exists {
+			if _, exists := keyToIndex[k]; !exists {
+				keyToIndex[k] = len(keys)
 				keys = append(keys, k)
 			}
-
-			uniqueKeys[k] = 1.0
+			set = append(set, keyToIndex[k])
 		}
+
+		sort.Ints(set)
+		sets = append(sets, set)
+	}
+
+	result := make(ExprSet)
+
+	for _, k := range minimumHittingSetIndices(sets, len(keys)) {
+		result[keys[k]] = true
 	}
 
-	for range keys {
-		def.Vars = append(def.Vars, 1.0)
+	return result, nil
+}
+
+// minimumHittingSetIndices returns a minimum hitting set of a list of non-empty sets of indices in [0, numKeys).
+// The result is computed exactly with a branch-and-bound search: at each step the search branches on the elements
+// of the smallest set not yet hit, and prunes a branch when the current size plus a lower bound (the number of
+// pairwise disjoint sets not yet hit) cannot improve the best hitting set found so far.
+func minimumHittingSetIndices(sets [][]int, numKeys int) []int {
+	hit := make([]bool, numKeys)
+	excluded := make([]bool, numKeys)
+	best := numKeys
+	bestSet := make([]bool, numKeys)
+
+	for k := range bestSet {
+		bestSet[k] = true
 	}
 
-	for range keys {
-		constr := [2]float64{0, 1}
-		def.Constraints = append(def.Constraints, constr)
+	isHit := func(set []int) bool {
+		for _, k := range set {
+			if hit[k] {
+				return true
+			}
+		}
+		return false
 	}
 
-	for _, xs := range quorums {
-		obj := make([]float64, 0)
-		obj = append(obj, 1)
-
-		for _, k := range keys {
-			if _, exists := xs[k]; exists {
-				obj = append(obj, 1)
-			} else {
-				obj = append(obj, 0)
+	// lowerBound greedily packs pairwise disjoint quorums that are not hit yet: each of them needs a distinct element.
+	lowerBound := func() int {
+		used := make([]bool, numKeys)
+		bound := 0
+
+		for _, set := range sets {
+			if isHit(set) {
+				continue
+			}
+
+			disjoint := true
+
+			for _, k := range set {
+				if used[k] {
+					disjoint = false
+					break
+				}
 			}
+
+			if !disjoint {
+				continue
+			}
+
+			for _, k := range set {
+				used[k] = true
+			}
+			bound++
 		}
 
-		obj = append(obj, math.Inf(1))
-		def.Objectives = append(def.Objectives, obj)
+		return bound
 	}
 
-	// Set up the optimization problem.
-	simp.EasyLoadDenseProblem(
-		def.Vars,
-		def.Constraints,
-		def.Objectives)
+	var search func(size int)
+
+	search = func(size int) {
+		var branch []int
+
+		for _, set := range sets {
+			if isHit(set) {
+				continue
+			}
+
+			candidates := make([]int, 0, len(set))
+
+			for _, k := range set {
+				if !excluded[k] {
+					candidates = append(candidates, k)
+				}
+			}
+
+			// The quorum can no longer be hit in this branch.
+			if len(candidates) == 0 {
+				return
+			}
+
+			if branch == nil || len(candidates) < len(branch) {
+				branch = candidates
+			}
+		}
+
+		if branch == nil {
+			if size < best {
+				best = size
+				copy(bestSet, hit)
+			}
+			return
+		}
 
-	simp.SetOptimizationDirection(clp.Minimize)
+		if size+lowerBound() >= best {
+			return
+		}
 
-	// Solve the optimization problem.
-	status := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
-	soln := simp.PrimalColumnSolution()
+		// The i-th branch includes branch[i] and excludes branch[:i], so that every hitting set is visited once.
+		for _, k := range branch {
+			hit[k] = true
+			search(size + 1)
+			hit[k] = false
+			excluded[k] = true
+		}
 
-	if status != clp.Optimal {
-		fmt.Println("Error")
+		for _, k := range branch {
+			excluded[k] = false
+		}
 	}
 
-	result := uint(0)
+	search(0)
 
-	for _, v := range soln {
-		result += uint(math.Round(v))
+	result := make([]int, 0, best)
+
+	for k, isInSet := range bestSet {
+		if isInSet {
+			result = append(result, k)
+		}
 	}
 
 	return result
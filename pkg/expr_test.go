@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNode(t *testing.T) {
@@ -16,6 +17,19 @@ func TestNode(t *testing.T) {
 	assert.Assert(t, c.String() == "c")
 }
 
+func TestNodeDefaults(t *testing.T) {
+	a := Node{Name: "a"}
+	assert.Equal(t, a.GetReadCapacity(), DefaultCapacity)
+	assert.Equal(t, a.GetWriteCapacity(), DefaultCapacity)
+	assert.Equal(t, a.GetLatency(), DefaultLatency)
+
+	b := NewNodeWithCapacityAndLatency("b", 1.5, 0.5, 250*time.Microsecond)
+	assert.Equal(t, b.GetReadCapacity(), 1.5)
+	assert.Equal(t, b.GetWriteCapacity(), 0.5)
+	assert.Equal(t, b.GetLatency(), 250*time.Microsecond)
+	assert.Equal(t, milliseconds(b.GetLatency()), 0.25)
+}
+
 func TestQuorums(t *testing.T) {
 	assertQuorums := func(e Quorum, xs [][]string) {
 		actual := make([]string, 0)
@@ -223,6 +237,8 @@ func TestResilience(t *testing.T) {
 		{a.Add(a).Add(c).Multiply(d.Add(e).Add(f)), 1},
 		{(a.Add(a).Add(a)).Multiply(d.Add(e).Add(f)), 0},
 		{(a.Multiply(b)).Add(b.Multiply(c)).Add(a.Multiply(d)).Add(a.Multiply(d).Multiply(e)), 1},
+		{(a.Multiply(b)).Add(b.Multiply(c)).Add(a.Multiply(c)), 1},
+		{(a.Multiply(b)).Add(b.Multiply(c)).Add(c.Multiply(d)).Add(d.Multiply(e)).Add(e.Multiply(a)), 2},
 	}
 
 	for _, tt := range tests {
@@ -336,3 +352,31 @@ func TestDupFree(t *testing.T) {
 		assert.Assert(t, expr.DupFree() == tt.isDupFree)
 	}
 }
+
+func TestMinHittingSet(t *testing.T) {
+	a, b, c, d, e := NewNode("a"), NewNode("b"), NewNode("c"), NewNode("d"), NewNode("e")
+
+	tests := []struct {
+		quorums  []ExprSet
+		expected uint
+	}{
+		{[]ExprSet{}, 0},
+		{[]ExprSet{{a: true}}, 1},
+		{[]ExprSet{{a: true, b: true}, {a: true, c: true}}, 1},
+		{[]ExprSet{{a: true}, {b: true}, {c: true}}, 3},
+		// The LP relaxation of the triangle is 1.5 with every variable set to 0.5.
+		{[]ExprSet{{a: true, b: true}, {b: true, c: true}, {a: true, c: true}}, 2},
+		// The LP relaxation of the 5-cycle is 2.5 with every variable set to 0.5.
+		{[]ExprSet{{a: true, b: true}, {b: true, c: true}, {c: true, d: true}, {d: true, e: true}, {e: true, a: true}}, 3},
+		{[]ExprSet{{a: true, b: true, c: true}, {c: true, d: true}, {d: true, e: true}, {a: true, e: true}}, 2},
+	}
+
+	for _, tt := range tests {
+		actual, err := minHittingSet(tt.quorums)
+		assert.NilError(t, err)
+		assert.Assert(t, actual == tt.expected, fmt.Sprintf("Actual: %d | Expected  %d", actual, tt.expected))
+	}
+
+	_, err := minHittingSet([]ExprSet{{a: true}, {}})
+	assert.Error(t, err, "an empty quorum cannot be hit")
+}
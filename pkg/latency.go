@@ -0,0 +1,443 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// defaultPercentile is the latency percentile optimized by TailLatency when StrategyOptions.Percentile is not set.
+const defaultPercentile = 0.99
+
+// maxLatency is the largest latency of the percentiles, about 146 years. It leaves room for the arithmetic of the
+// binary search on the latency.
+const maxLatency = time.Duration(1 << 62)
+
+// LatencyDistribution describes the distribution of the latency of a node. The latencies of the nodes are
+// independent. Implementations must be comparable, since nodes are used as map keys.
+type LatencyDistribution interface {
+	// CDF returns the probability that the latency is at most the given latency.
+	CDF(latency time.Duration) float64
+	// Quantile returns the smallest latency whose CDF is at least p.
+	Quantile(p float64) time.Duration
+	// Mean returns the mean latency.
+	Mean() time.Duration
+}
+
+// ConstantLatency is a LatencyDistribution where the latency is always the same.
+type ConstantLatency struct {
+	Latency time.Duration
+}
+
+// CDF returns the probability that the latency is at most the given latency.
+func (c ConstantLatency) CDF(latency time.Duration) float64 {
+	if latency >= c.Latency {
+		return 1
+	}
+
+	return 0
+}
+
+// Quantile returns the smallest latency whose CDF is at least p.
+func (c ConstantLatency) Quantile(p float64) time.Duration {
+	return c.Latency
+}
+
+// Mean returns the mean latency.
+func (c ConstantLatency) Mean() time.Duration {
+	return c.Latency
+}
+
+// LatencyHistogram is a LatencyDistribution over a finite set of latencies, e.g. measured ones.
+type LatencyHistogram struct {
+	latencies  []time.Duration
+	cumulative []float64
+	mean       time.Duration
+}
+
+// NewLatencyHistogram returns a LatencyHistogram given the weight of each latency, weights are normalized.
+func NewLatencyHistogram(weights map[time.Duration]Weight) (*LatencyHistogram, error) {
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("a latency histogram cannot be empty")
+	}
+
+	total := 0.0
+
+	for latency, w := range weights {
+		if latency < 0 {
+			return nil, fmt.Errorf("a latency histogram cannot have negative latencies")
+		}
+
+		if w < 0 {
+			return nil, fmt.Errorf("a latency histogram cannot have negative weights")
+		}
+
+		total += w
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("a latency histogram cannot have zero weight")
+	}
+
+	h := &LatencyHistogram{}
+
+	for latency, w := range weights {
+		if w > 0 {
+			h.latencies = append(h.latencies, latency)
+		}
+	}
+
+	sort.Slice(h.latencies, func(i, j int) bool { return h.latencies[i] < h.latencies[j] })
+
+	cumulative, mean := 0.0, 0.0
+
+	for _, latency := range h.latencies {
+		p := weights[latency] / total
+		cumulative += p
+		mean += p * float64(latency)
+		h.cumulative = append(h.cumulative, cumulative)
+	}
+
+	h.mean = time.Duration(math.Round(mean))
+
+	return h, nil
+}
+
+// CDF returns the probability that the latency is at most the given latency.
+func (h *LatencyHistogram) CDF(latency time.Duration) float64 {
+	i := sort.Search(len(h.latencies), func(i int) bool { return h.latencies[i] > latency })
+
+	if i == 0 {
+		return 0
+	}
+
+	return h.cumulative[i-1]
+}
+
+// Quantile returns the smallest latency whose CDF is at least p.
+func (h *LatencyHistogram) Quantile(p float64) time.Duration {
+	for i, c := range h.cumulative {
+		if c >= p-1e-12 {
+			return h.latencies[i]
+		}
+	}
+
+	return h.latencies[len(h.latencies)-1]
+}
+
+// Mean returns the mean latency.
+func (h *LatencyHistogram) Mean() time.Duration {
+	return h.mean
+}
+
+// LogNormalLatency is a log-normal LatencyDistribution, a common model of heavy tailed latencies: the logarithm of
+// the latency is normally distributed with mean ln(Median) and standard deviation Sigma.
+type LogNormalLatency struct {
+	Median time.Duration
+	Sigma  float64
+}
+
+// CDF returns the probability that the latency is at most the given latency.
+func (l LogNormalLatency) CDF(latency time.Duration) float64 {
+	if latency <= 0 {
+		return 0
+	}
+
+	if l.Sigma == 0 {
+		return ConstantLatency{Latency: l.Median}.CDF(latency)
+	}
+
+	return 0.5 * math.Erfc(-math.Log(float64(latency)/float64(l.Median))/(l.Sigma*math.Sqrt2))
+}
+
+// Quantile returns the smallest latency whose CDF is at least p.
+func (l LogNormalLatency) Quantile(p float64) time.Duration {
+	if p <= 0 {
+		return 0
+	}
+
+	if p >= 1 {
+		return maxLatency
+	}
+
+	return toLatency(math.Ceil(float64(l.Median) * math.Exp(l.Sigma*math.Sqrt2*math.Erfinv(2*p-1))))
+}
+
+// Mean returns the mean latency.
+func (l LogNormalLatency) Mean() time.Duration {
+	return toLatency(math.Round(float64(l.Median) * math.Exp(l.Sigma*l.Sigma/2)))
+}
+
+// toLatency converts a number of nanoseconds to a latency, capped at maxLatency.
+func toLatency(nanoseconds float64) time.Duration {
+	if math.IsNaN(nanoseconds) || nanoseconds >= float64(maxLatency) {
+		return maxLatency
+	}
+
+	return time.Duration(nanoseconds)
+}
+
+// LatencyPercentile returns the p-th percentile (e.g. 0.99) of the latency of the strategy given a read and write
+// Distribution, in milliseconds. The latency of an operation is the time until the nodes of the chosen quorum that
+// answered form a quorum, given the LatencyDistribution of each node.
+func (s Strategy) LatencyPercentile(p float64, rf *Distribution, wf *Distribution) (float64, error) {
+	if p <= 0 || p >= 1 {
+		return 0, fmt.Errorf("percentile must be in (0, 1)")
+	}
+
+	d, err := canonicalizeReadsWrites(rf, wf)
+
+	if err != nil {
+		return 0, err
+	}
+
+	fr := 0.0
+
+	for f, w := range d {
+		fr += f * w
+	}
+
+	reads := make([]quorumCompletion, 0, len(s.SigmaR.Values))
+	writes := make([]quorumCompletion, 0, len(s.SigmaW.Values))
+
+	for _, r := range s.SigmaR.Values {
+		reads = append(reads, newQuorumCompletion(s.Qs.reads, r.Quorum))
+	}
+
+	for _, w := range s.SigmaW.Values {
+		writes = append(writes, newQuorumCompletion(s.Qs.writes, w.Quorum))
+	}
+
+	latency, err := searchLatency(s.Qs.latencyUpperBound(p), func(latency time.Duration) (bool, error) {
+		cdf := 0.0
+
+		for i, r := range s.SigmaR.Values {
+			p, err := reads[i].cdf(latency)
+
+			if err != nil {
+				return false, err
+			}
+
+			cdf += fr * r.Probability * p
+		}
+
+		for i, w := range s.SigmaW.Values {
+			p, err := writes[i].cdf(latency)
+
+			if err != nil {
+				return false, err
+			}
+
+			cdf += (1 - fr) * w.Probability * p
+		}
+
+		return cdf >= p-1e-9, nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return milliseconds(latency), nil
+}
+
+// tailLatencyStrategy returns the strategy minimizing the sb.Percentile percentile of the latency.
+//
+// For a fixed latency t, the probability that an operation completes within t is linear in the quorum probabilities,
+// so the linear program maximizing it, under the limits of the StrategyOptions, tells if the percentile can be t.
+// The smallest such t is found with a binary search. The linear program is built once, only its objective changes
+// with t.
+func (qs QuorumSystem) tailLatencyStrategy(sb *StrategyOptions, readQuorums []ExprSet, writeQuorums []ExprSet,
+	readFraction DistributionValues) (*Strategy, error) {
+	fr := 0.0
+
+	for f, p := range readFraction {
+		fr += f * p
+	}
+
+	problem, err := qs.buildLoadOptimalProblem(TailLatency, readQuorums, writeQuorums, readFraction,
+		sb.LoadLimit, sb.NetworkLimit, sb.LatencyLimit, sb.CostLimit, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reads := make([]quorumCompletion, 0, len(problem.readQuorumVars))
+	writes := make([]quorumCompletion, 0, len(problem.writeQuorumVars))
+
+	for _, v := range problem.readQuorumVars {
+		reads = append(reads, newQuorumCompletion(qs.reads, v.Quorum))
+	}
+
+	for _, v := range problem.writeQuorumVars {
+		writes = append(writes, newQuorumCompletion(qs.writes, v.Quorum))
+	}
+
+	solve := func(latency time.Duration) (lpSolution, error) {
+		for i, v := range problem.readQuorumVars {
+			p, err := reads[i].cdf(latency)
+
+			if err != nil {
+				return lpSolution{}, err
+			}
+
+			problem.def.Vars[v.Index] = -fr * p
+		}
+
+		for i, v := range problem.writeQuorumVars {
+			p, err := writes[i].cdf(latency)
+
+			if err != nil {
+				return lpSolution{}, err
+			}
+
+			problem.def.Vars[v.Index] = -(1 - fr) * p
+		}
+
+		return problem.def.solve()
+	}
+
+	latency, err := searchLatency(qs.latencyUpperBound(sb.Percentile), func(latency time.Duration) (bool, error) {
+		solution, err := solve(latency)
+
+		if err != nil {
+			return false, err
+		}
+
+		return -solution.Objective >= sb.Percentile-1e-9, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	solution, err := solve(latency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := problem.strategy(qs, solution)
+
+	return &strategy, nil
+}
+
+// quorumCompletion computes the probability that a quorum of an Expr completes within a latency.
+type quorumCompletion struct {
+	e      Expr
+	quorum ExprSet
+	// minimal is true when no node can be left out of the quorum, which then completes when all its nodes answer.
+	minimal bool
+}
+
+// newQuorumCompletion returns the quorumCompletion of a quorum of e.
+func newQuorumCompletion(e Expr, quorum ExprSet) quorumCompletion {
+	c := quorumCompletion{e: e, quorum: quorum, minimal: true}
+
+	for x := range quorum {
+		rest := make(ExprSet, len(quorum)-1)
+
+		for y := range quorum {
+			if y != x {
+				rest[y] = true
+			}
+		}
+
+		if e.IsQuorum(rest) {
+			c.minimal = false
+			break
+		}
+	}
+
+	return c
+}
+
+// cdf returns the probability that the quorum completes within the given latency, i.e. that the nodes of the quorum
+// answering within the latency form a quorum of e. For a minimal quorum, it is the product of the CDFs of its nodes.
+// Otherwise, e.g. for the quorums resilient to F failures, it is the availability of e when the other nodes fail.
+func (c quorumCompletion) cdf(latency time.Duration) (float64, error) {
+	if c.minimal {
+		p := 1.0
+
+		for x := range c.quorum {
+			p *= x.(Node).GetLatencyDistribution().CDF(latency)
+		}
+
+		return p, nil
+	}
+
+	failureProbabilities := make(FailureProbabilities)
+
+	for n := range c.e.GetNodes() {
+		failureProbabilities[n] = 1
+	}
+
+	for x := range c.quorum {
+		n := x.(Node)
+		failureProbabilities[n] = 1 - n.GetLatencyDistribution().CDF(latency)
+	}
+
+	a, err := availability(c.e, failureProbabilities)
+
+	return a.Probability, err
+}
+
+// latencyUpperBound returns a latency within which any quorum completes with probability at least p: it is at least
+// the p' quantile of every node, where p' = 1 - (1 - p) / n, so that all the n nodes answer with probability p.
+func (qs QuorumSystem) latencyUpperBound(p float64) time.Duration {
+	nodes := qs.GetNodes()
+	q := 1 - (1-p)/float64(len(nodes))
+	upper := time.Duration(0)
+
+	for n := range nodes {
+		if latency := n.GetLatencyDistribution().Quantile(q); latency > upper {
+			upper = latency
+		}
+	}
+
+	if upper > maxLatency {
+		return maxLatency
+	}
+
+	return upper
+}
+
+// searchLatency returns the smallest latency, up to the nanosecond, in [0, upper] for which reached returns true,
+// given that reached is monotone. It returns an error if reached returns false for upper.
+func searchLatency(upper time.Duration, reached func(latency time.Duration) (bool, error)) (time.Duration, error) {
+	ok, err := reached(0)
+
+	if err != nil || ok {
+		return 0, err
+	}
+
+	ok, err = reached(upper)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, fmt.Errorf("the percentile is not reached within %s", upper)
+	}
+
+	lower := time.Duration(0)
+
+	for upper-lower > 1 {
+		middle := lower + (upper-lower)/2
+		ok, err := reached(middle)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			upper = middle
+		} else {
+			lower = middle
+		}
+	}
+
+	return upper, nil
+}
@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLatencyDistributions(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	h, err := NewLatencyHistogram(map[time.Duration]Weight{10 * time.Millisecond: 1, 100 * time.Millisecond: 3})
+	assert.NilError(t, err)
+	assert.Assert(t, h.CDF(5*time.Millisecond) == 0)
+	assert.Assert(t, math.Abs(h.CDF(10*time.Millisecond)-0.25) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(h.CDF(50*time.Millisecond)-0.25) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(h.CDF(100*time.Millisecond)-1) < float64EqualityThreshold)
+	assert.Equal(t, h.Quantile(0.25), 10*time.Millisecond)
+	assert.Equal(t, h.Quantile(0.5), 100*time.Millisecond)
+	assert.Equal(t, h.Mean(), 77500*time.Microsecond)
+
+	_, err = NewLatencyHistogram(map[time.Duration]Weight{})
+	assert.Error(t, err, "a latency histogram cannot be empty")
+
+	_, err = NewLatencyHistogram(map[time.Duration]Weight{-time.Millisecond: 1})
+	assert.Error(t, err, "a latency histogram cannot have negative latencies")
+
+	_, err = NewLatencyHistogram(map[time.Duration]Weight{time.Millisecond: -1})
+	assert.Error(t, err, "a latency histogram cannot have negative weights")
+
+	_, err = NewLatencyHistogram(map[time.Duration]Weight{time.Millisecond: 0})
+	assert.Error(t, err, "a latency histogram cannot have zero weight")
+
+	l := LogNormalLatency{Median: 10 * time.Millisecond, Sigma: 0.5}
+	assert.Assert(t, math.Abs(l.CDF(10*time.Millisecond)-0.5) < float64EqualityThreshold)
+	assert.Assert(t, l.CDF(0) == 0)
+	assert.Equal(t, l.Quantile(0.5), 10*time.Millisecond)
+	assert.Assert(t, math.Abs(l.CDF(l.Quantile(0.99))-0.99) < 1e-6, fmt.Sprintf("Actual:%f", l.CDF(l.Quantile(0.99))))
+	assert.Equal(t, l.Mean(), time.Duration(math.Round(1e7*math.Exp(0.125))))
+
+	// The heavy tails are capped at maxLatency.
+	l = LogNormalLatency{Median: 10 * time.Millisecond, Sigma: 100}
+	assert.Equal(t, l.Quantile(1), maxLatency)
+	assert.Equal(t, l.Quantile(0.99), maxLatency)
+	assert.Equal(t, l.Mean(), maxLatency)
+
+	c := ConstantLatency{Latency: time.Millisecond}
+	assert.Assert(t, c.CDF(time.Millisecond-1) == 0)
+	assert.Assert(t, c.CDF(time.Millisecond) == 1)
+	assert.Equal(t, c.Quantile(0.99), time.Millisecond)
+
+	// Without latency, the mean of the distribution is the latency of the node.
+	n := NewNode("a").WithLatencyDistribution(h)
+	assert.Equal(t, n.GetLatency(), 77500*time.Microsecond)
+	assert.Equal(t, NewNodeWithLatency("b", time.Millisecond).GetLatencyDistribution(), LatencyDistribution(c))
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	h, err := NewLatencyHistogram(map[time.Duration]Weight{1 * time.Millisecond: 0.9, 100 * time.Millisecond: 0.1})
+	assert.NilError(t, err)
+
+	a := NewNode("a").WithLatencyDistribution(h)
+	b := NewNodeWithLatency("b", 10*time.Millisecond)
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	// A write waits for both nodes: it completes within 10ms when a answers within 1ms.
+	write := ExprSet{a: true, b: true}
+	p, err := newQuorumCompletion(qs.writes, write).cdf(10 * time.Millisecond)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p-0.9) < float64EqualityThreshold)
+
+	p, err = newQuorumCompletion(qs.writes, write).cdf(9 * time.Millisecond)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p) < float64EqualityThreshold)
+
+	p, err = newQuorumCompletion(qs.reads, ExprSet{a: true}).cdf(time.Millisecond)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p-0.9) < float64EqualityThreshold)
+
+	// The quorum of a majority resilient to one failure completes when any two of its nodes answer.
+	c := NewNodeWithLatency("c", 20*time.Millisecond)
+	majority, err := NewChoose(2, []Expr{a, b, c})
+	assert.NilError(t, err)
+
+	resilient := newQuorumCompletion(majority, ExprSet{a: true, b: true, c: true})
+	assert.Assert(t, !resilient.minimal)
+
+	p, err = resilient.cdf(10 * time.Millisecond)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p-0.9) < float64EqualityThreshold)
+
+	p, err = resilient.cdf(20 * time.Millisecond)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p-1) < float64EqualityThreshold)
+
+	strategyOptions := StrategyOptions{
+		Optimize: Latency,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}
+
+	var rf Distribution = QuorumDistribution{values: DistributionValues{1: 1}}
+	var wf Distribution
+
+	// The mean latency of a is 10.9ms, so the latency optimal strategy reads from b.
+	strategy, err := qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	p85, err := strategy.LatencyPercentile(0.85, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p85-10) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", p85))
+
+	// Reading from a completes within 1ms 90% of the time.
+	strategyOptions.Optimize = TailLatency
+	strategyOptions.Percentile = 0.85
+
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(strategy.nodeToReadProbability[a]-1) < float64EqualityThreshold)
+
+	p85, err = strategy.LatencyPercentile(0.85, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p85-1) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", p85))
+
+	p99, err := strategy.LatencyPercentile(0.99, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p99-100) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", p99))
+
+	// The 99th percentile is 10ms as long as at most 10% of the reads go to a.
+	strategyOptions.Percentile = 0
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+	assert.Assert(t, strategy.nodeToReadProbability[a] <= 0.1+float64EqualityThreshold)
+
+	p99, err = strategy.LatencyPercentile(0.99, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p99-10) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", p99))
+
+	_, err = strategy.LatencyPercentile(1, &rf, &wf)
+	assert.Error(t, err, "percentile must be in (0, 1)")
+
+	strategyOptions.Percentile = 1
+	_, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.Error(t, err, "percentile must be in (0, 1)")
+
+	// A percentile beyond maxLatency is an error rather than a bogus latency.
+	heavy := NewNode("c").WithLatencyDistribution(LogNormalLatency{Median: time.Millisecond, Sigma: 100})
+	strategy, err = NewQuorumSystemWithReads(heavy).Strategy(
+		initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.NilError(t, err)
+
+	_, err = strategy.LatencyPercentile(0.99, &rf, &wf)
+	assert.Error(t, err, fmt.Sprintf("the percentile is not reached within %s", maxLatency))
+
+	// The percentiles of the minimal quorums do not depend on the number of repeated nodes of the expression.
+	nodes := make([]Expr, 0, maxRepeatedNodes+1)
+
+	for i := 0; i <= maxRepeatedNodes; i++ {
+		nodes = append(nodes, NewNodeWithLatency(fmt.Sprintf("n%d", i), time.Duration(i+1)*time.Millisecond))
+	}
+
+	repeated := NewQuorumSystemWithReads(Or{Es: []Expr{And{Es: nodes}, Or{Es: nodes}}})
+	strategy, err = repeated.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: TailLatency, ReadFraction: rf}))
+	assert.NilError(t, err)
+
+	p99, err = strategy.LatencyPercentile(0.99, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(p99-1) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", p99))
+
+	latency, err := searchLatency(time.Second, func(time.Duration) (bool, error) { return true, nil })
+	assert.NilError(t, err)
+	assert.Equal(t, latency, time.Duration(0))
+
+	latency, err = searchLatency(time.Second, func(l time.Duration) (bool, error) { return l >= time.Millisecond, nil })
+	assert.NilError(t, err)
+	assert.Equal(t, latency, time.Millisecond)
+}
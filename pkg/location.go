@@ -0,0 +1,204 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ClientLocation describes a location of the clients (e.g. a region), the share of the operations issued from it and
+// its latency to each node.
+type ClientLocation struct {
+	Name string
+	// Share defines the share of the operations issued from the location, shares are normalized over the locations.
+	Share Weight
+	// Latencies defines the latency from the location to each node, indexed by node name. A node without latency from
+	// the location has its own latency.
+	Latencies map[string]time.Duration
+}
+
+// LocationStrategies describes the strategies of the client locations, jointly optimized on the shared nodes.
+type LocationStrategies struct {
+	// Strategies is the strategy of each location, indexed by location name.
+	Strategies map[string]*Strategy
+	// Load is the load of the quorum system given the operations of every location.
+	Load float64
+	// NetworkLoad is the network load averaged over the locations, weighted by their shares.
+	NetworkLoad float64
+	// Latency is the latency averaged over the locations, weighted by their shares, in milliseconds.
+	Latency float64
+	// Cost is the cost of an operation averaged over the locations, weighted by their shares.
+	Cost float64
+}
+
+// latency returns the latency from the location to the node.
+func (l ClientLocation) latency(n Node) time.Duration {
+	if latency, ok := l.Latencies[n.Name]; ok {
+		return latency
+	}
+
+	return n.GetLatency()
+}
+
+// LocationLatency returns the latency of the strategy, in milliseconds, for the clients in the given location.
+func (s Strategy) LocationLatency(location ClientLocation, rf *Distribution, wf *Distribution) (float64, error) {
+	return s.latency(rf, wf, location.latency)
+}
+
+// LocationStrategies returns one strategy per client location. The strategies are optimized together, in the linear
+// program of QuorumSystem.Strategy with one set of quorum probabilities per location: the load of each node sums the
+// operations of every location, weighted by their shares, so the strategies share the node capacities. Load, Network,
+// Latency and Cost are optimized and limited on the whole, e.g. the latency is the latency of each location weighted
+// by its share.
+func (qs QuorumSystem) LocationStrategies(locations []ClientLocation,
+	strategyOptions StrategyOptions) (LocationStrategies, error) {
+	shares, err := qs.locationShares(locations)
+
+	if err != nil {
+		return LocationStrategies{}, err
+	}
+
+	sb, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(strategyOptions))
+
+	if err != nil {
+		return LocationStrategies{}, err
+	}
+
+	if sb.Optimize == Weighted || sb.Optimize == TailLatency {
+		return LocationStrategies{}, fmt.Errorf("optimizing for %s is not supported with client locations", sb.Optimize)
+	}
+
+	clients := make([]lpClient, 0, len(locations))
+
+	for c, location := range locations {
+		clients = append(clients, lpClient{share: shares[c], latency: location.latency})
+	}
+
+	problem, err := qs.buildClientsLoadOptimalProblem(sb.Optimize, rq, wq, d, sb.LoadLimit, sb.NetworkLimit,
+		sb.LatencyLimit, sb.CostLimit, nil, clients)
+
+	if err != nil {
+		return LocationStrategies{}, err
+	}
+
+	solution, err := problem.def.solve()
+
+	if err != nil {
+		return LocationStrategies{}, err
+	}
+
+	result := LocationStrategies{Strategies: make(map[string]*Strategy)}
+	strategies := make([]*Strategy, 0, len(locations))
+
+	for c, location := range locations {
+		client := loadOptimalProblem{
+			readQuorumVars:  problem.clientReadQuorumVars[c],
+			writeQuorumVars: problem.clientWriteQuorumVars[c],
+		}
+		strategy := client.strategy(qs, solution)
+
+		result.Strategies[location.Name] = &strategy
+		strategies = append(strategies, &strategy)
+	}
+
+	nodes := qs.GetNodesAsArray()
+
+	for _, fr := range problem.fractions {
+		maxLoad := 0.0
+
+		for _, n := range nodes {
+			load := 0.0
+
+			for c, s := range strategies {
+				load += shares[c] * s.getNodeLoad(n, fr)
+			}
+
+			maxLoad = math.Max(maxLoad, load)
+		}
+
+		result.Load += d[fr] * maxLoad
+	}
+
+	for c, location := range locations {
+		networkLoad, err := strategies[c].NetworkLoad(&sb.ReadFraction, &sb.WriteFraction)
+
+		if err != nil {
+			return LocationStrategies{}, err
+		}
+
+		latency, err := strategies[c].LocationLatency(location, &sb.ReadFraction, &sb.WriteFraction)
+
+		if err != nil {
+			return LocationStrategies{}, err
+		}
+
+		cost, err := strategies[c].Cost(&sb.ReadFraction, &sb.WriteFraction)
+
+		if err != nil {
+			return LocationStrategies{}, err
+		}
+
+		result.NetworkLoad += shares[c] * networkLoad
+		result.Latency += shares[c] * latency
+		result.Cost += shares[c] * cost
+	}
+
+	return result, nil
+}
+
+// locationShares validates the client locations and returns their normalized shares.
+func (qs QuorumSystem) locationShares(locations []ClientLocation) ([]float64, error) {
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("at least one client location must be given")
+	}
+
+	names := make(map[string]bool)
+	total := 0.0
+
+	for _, l := range locations {
+		if names[l.Name] {
+			return nil, fmt.Errorf("client location %s is given more than once", l.Name)
+		}
+
+		names[l.Name] = true
+
+		if l.Share < 0 {
+			return nil, fmt.Errorf("the share of client location %s must be >= 0", l.Name)
+		}
+
+		total += l.Share
+
+		for name, latency := range l.Latencies {
+			if _, ok := qs.nameToNode[name]; !ok {
+				return nil, fmt.Errorf("node %s not found", name)
+			}
+
+			if latency < 0 {
+				return nil, fmt.Errorf("the latency from client location %s to node %s must be >= 0", l.Name, name)
+			}
+		}
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("the client locations cannot have zero share")
+	}
+
+	shares := make([]float64, 0, len(locations))
+
+	for _, l := range locations {
+		shares = append(shares, l.Share/total)
+	}
+
+	return shares, nil
+}
+
+// quorumNodes returns the nodes of the quorum system in the quorum.
+func quorumNodes(qs QuorumSystem, quorum ExprSet) []Node {
+	nodes := make([]Node, 0, len(quorum))
+
+	for x := range quorum {
+		nodes = append(nodes, qs.GetNodeByName(x.String()))
+	}
+
+	return nodes
+}
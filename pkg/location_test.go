@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLocationStrategies(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b := NewNode("a"), NewNode("b")
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	east := ClientLocation{Name: "east", Share: 3,
+		Latencies: map[string]time.Duration{"a": 1 * time.Millisecond, "b": 10 * time.Millisecond}}
+	west := ClientLocation{Name: "west", Share: 1,
+		Latencies: map[string]time.Duration{"a": 10 * time.Millisecond, "b": 1 * time.Millisecond}}
+
+	strategyOptions := StrategyOptions{
+		Optimize: Latency,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}
+
+	// Each location reads from its closest node.
+	result, err := qs.LocationStrategies([]ClientLocation{east, west}, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(result.Latency-1) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", result.Latency))
+	assert.Assert(t, math.Abs(result.Load-0.75) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", result.Load))
+	assert.Assert(t, math.Abs(result.NetworkLoad-1) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(result.Strategies["east"].nodeToReadProbability[a]-1) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(result.Strategies["west"].nodeToReadProbability[b]-1) < float64EqualityThreshold)
+
+	// With a shared load limit, part of the east reads move to b: 0.75 * p(a) <= 0.5.
+	loadLimit := 0.5
+	strategyOptions.LoadLimit = &loadLimit
+
+	result, err = qs.LocationStrategies([]ClientLocation{east, west}, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(result.Load-0.5) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", result.Load))
+	assert.Assert(t, math.Abs(result.Strategies["east"].nodeToReadProbability[a]-2.0/3) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(result.Latency-3.25) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", result.Latency))
+
+	var rf Distribution = QuorumDistribution{values: DistributionValues{1: 1}}
+	var wf Distribution
+
+	latency, err := result.Strategies["west"].LocationLatency(west, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(latency-1) < float64EqualityThreshold)
+
+	// A single location without latencies has the metrics of QuorumSystem.Strategy, the programs are the same.
+	c, d := NewNodeWithCapacityAndLatency("c", 2, 1, 2*time.Millisecond), NewNodeWithCapacity("d", 1, 2)
+	e := NewNodeWithCapacityAndLatency("e", 1, 1, 3*time.Millisecond).WithCosts(1, 2)
+	majority, err := NewChoose(2, []Expr{c, d, e})
+	assert.NilError(t, err)
+
+	majorityQs := NewQuorumSystemWithReads(majority)
+	rf = QuorumDistribution{values: DistributionValues{0.2: 1, 0.8: 3}}
+
+	for _, optimize := range []OptimizeType{Load, Network, Latency, Cost} {
+		options := StrategyOptions{Optimize: optimize, ReadFraction: rf}
+
+		strategy, err := majorityQs.Strategy(initializeStrategyOptions(options))
+		assert.NilError(t, err)
+
+		expected, err := strategy.metric(optimize, defaultPercentile, &rf, &wf)
+		assert.NilError(t, err)
+
+		result, err = majorityQs.LocationStrategies([]ClientLocation{{Name: "any", Share: 1}}, options)
+		assert.NilError(t, err)
+
+		actual := map[OptimizeType]float64{
+			Load: result.Load, Network: result.NetworkLoad, Latency: result.Latency, Cost: result.Cost}[optimize]
+		assert.Assert(t, math.Abs(actual-expected) < float64EqualityThreshold,
+			fmt.Sprintf("%s: expected %f, actual %f", optimize, expected, actual))
+	}
+
+	_, err = qs.LocationStrategies([]ClientLocation{east, east}, strategyOptions)
+	assert.Error(t, err, "client location east is given more than once")
+
+	_, err = qs.LocationStrategies([]ClientLocation{{Name: "north", Latencies: map[string]time.Duration{"x": 0}}},
+		strategyOptions)
+	assert.Error(t, err, "node x not found")
+
+	_, err = qs.LocationStrategies([]ClientLocation{{Name: "north"}}, strategyOptions)
+	assert.Error(t, err, "the client locations cannot have zero share")
+}
@@ -0,0 +1,58 @@
+package pkg
+
+import "fmt"
+
+// PayloadSizes describes the size of the message sent to each node of a quorum by the read and write operations,
+// e.g. in bytes.
+type PayloadSizes struct {
+	Read  float64
+	Write float64
+}
+
+// DefaultPayloadSizes are the payload sizes of a quorum system without payload sizes: every message counts as 1, so
+// that the network load is the number of nodes contacted.
+var DefaultPayloadSizes = PayloadSizes{Read: 1, Write: 1}
+
+// WithPayloadSizes returns a copy of the quorum system with the given payload sizes. Together with the link costs of
+// the nodes, they turn the network load into the bytes or the cost of the messages sent by an operation.
+func (qs QuorumSystem) WithPayloadSizes(sizes PayloadSizes) (QuorumSystem, error) {
+	if sizes.Read < 0 || sizes.Write < 0 {
+		return QuorumSystem{}, fmt.Errorf("payload sizes must be >= 0")
+	}
+
+	qs.payloadSizes = &sizes
+
+	return qs, nil
+}
+
+// GetPayloadSizes returns the payload sizes of the quorum system, DefaultPayloadSizes when they are not set.
+func (qs QuorumSystem) GetPayloadSizes() PayloadSizes {
+	if qs.payloadSizes == nil {
+		return DefaultPayloadSizes
+	}
+
+	return *qs.payloadSizes
+}
+
+// readQuorumNetworkLoad returns the network load of a read on the quorum: the read payload sent to each node of the
+// quorum, times the link cost of the node.
+func (qs QuorumSystem) readQuorumNetworkLoad(quorum ExprSet) float64 {
+	return qs.GetPayloadSizes().Read * linkCost(qs, quorum)
+}
+
+// writeQuorumNetworkLoad returns the network load of a write on the quorum: the write payload sent to each node of
+// the quorum, times the link cost of the node.
+func (qs QuorumSystem) writeQuorumNetworkLoad(quorum ExprSet) float64 {
+	return qs.GetPayloadSizes().Write * linkCost(qs, quorum)
+}
+
+// linkCost returns the sum of the link costs of the nodes of the quorum.
+func linkCost(qs QuorumSystem, quorum ExprSet) float64 {
+	cost := 0.0
+
+	for _, n := range quorumNodes(qs, quorum) {
+		cost += n.GetLinkCost()
+	}
+
+	return cost
+}
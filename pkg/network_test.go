@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestWeightedNetworkLoad(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b := NewNode("a"), NewNode("b").WithLinkCost(10)
+	qs, err := NewQuorumSystemWithReads(a.Add(b)).WithPayloadSizes(PayloadSizes{Read: 100, Write: 1000})
+	assert.NilError(t, err)
+
+	strategyOptions := StrategyOptions{
+		Optimize: Network,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{0.5: 1}},
+	}
+
+	// The reads go to a, the cheapest link, and the writes go to both nodes.
+	strategy, err := qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(strategy.nodeToReadProbability[a]-1) < float64EqualityThreshold)
+
+	networkLoad, err := strategy.NetworkLoad(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(networkLoad-5550) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", networkLoad))
+
+	// Balancing the reads costs 0.5 * 0.5 * 100 * 9 more.
+	networkLimit := 5775.0
+	strategyOptions.Optimize = Load
+	strategyOptions.NetworkLimit = &networkLimit
+
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(strategy.nodeToReadProbability[a]-0.5) < float64EqualityThreshold)
+
+	networkLimit = 5600
+	strategy, err = qs.Strategy(initializeStrategyOptions(strategyOptions))
+	assert.NilError(t, err)
+
+	networkLoad, err = strategy.NetworkLoad(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, networkLoad <= networkLimit+float64EqualityThreshold, fmt.Sprintf("Actual:%f", networkLoad))
+
+	// The payload sizes are kept by the what-if changes.
+	changed, err := qs.WithNodeOverrides(map[string]NodeOverride{"b": {LinkCost: &networkLimit}})
+	assert.NilError(t, err)
+	assert.Equal(t, changed.GetPayloadSizes(), PayloadSizes{Read: 100, Write: 1000})
+	assert.Equal(t, changed.GetNodeByName("b").GetLinkCost(), networkLimit)
+
+	// Without payload sizes and link costs, the network load is the number of nodes contacted.
+	plain := NewQuorumSystemWithReads(NewNode("a").Add(NewNode("b")))
+	assert.Equal(t, plain.GetPayloadSizes(), DefaultPayloadSizes)
+	assert.Equal(t, plain.readQuorumNetworkLoad(ExprSet{NewNode("a"): true, NewNode("b"): true}), 2.0)
+
+	_, err = qs.WithPayloadSizes(PayloadSizes{Read: -1})
+	assert.Error(t, err, "payload sizes must be >= 0")
+
+	_, err = NewQuorumSystemWithReads(a.Add(NewNode("c").WithLinkCost(-1))).Strategy(
+		initializeStrategyOptions(strategyOptions))
+	assert.Error(t, err, "the link cost of node c must be >= 0")
+}
@@ -0,0 +1,297 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ParetoPoint describes a strategy on the Pareto frontier and its metrics.
+type ParetoPoint struct {
+	Strategy    *Strategy
+	Load        float64
+	NetworkLoad float64
+	Latency     float64
+	Cost        float64
+}
+
+// ParetoFrontier returns the non-dominated strategies trading off two or three of Load, Network, Latency and Cost, sorted by
+// increasing value of the first objective.
+//
+// The frontier is computed with the epsilon-constraint method: each objective but the first is limited to points
+// evenly spaced values over its range on the frontier (10 when points is 0), and the first objective is optimized for
+// every combination of these limits. The Optimize option and the limits on the traded-off objectives are ignored, the other options (e.g. the
+// limit on an objective that is not traded off, F or the read fraction) apply to every strategy.
+func (qs QuorumSystem) ParetoFrontier(objectives []OptimizeType, points uint,
+	strategyOptions StrategyOptions) ([]ParetoPoint, error) {
+	if len(objectives) < 2 || len(objectives) > 3 {
+		return nil, fmt.Errorf("two or three objectives must be given")
+	}
+
+	seen := make(map[OptimizeType]bool)
+
+	for _, o := range objectives {
+		if o != Load && o != Network && o != Latency && o != Cost {
+			return nil, fmt.Errorf("unknown objective %s", o)
+		}
+
+		if seen[o] {
+			return nil, fmt.Errorf("objective %s is given more than once", o)
+		}
+
+		seen[o] = true
+	}
+
+	if points == 0 {
+		points = 10
+	}
+
+	if points < 2 {
+		return nil, fmt.Errorf("points must be >= 2")
+	}
+
+	options := strategyOptions
+	options.Optimize = objectives[0]
+
+	for _, o := range objectives {
+		setLimit(&options, o, nil)
+	}
+
+	sb, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(options))
+
+	if err != nil {
+		return nil, err
+	}
+
+	evaluate := func(optimize OptimizeType, limits map[OptimizeType]float64) (ParetoPoint, error) {
+		o := *sb
+		o.Optimize = optimize
+
+		for objective, limit := range limits {
+			l := limit
+			setLimit(&o, objective, &l)
+		}
+
+		strategy, err := qs.loadOptimalStrategy(o.Optimize, rq, wq, d, o.LoadLimit, o.NetworkLimit, o.LatencyLimit,
+			o.CostLimit, nil)
+
+		if err != nil {
+			return ParetoPoint{}, err
+		}
+
+		return strategy.paretoPoint(&sb.ReadFraction, &sb.WriteFraction)
+	}
+
+	// The anchors optimize each objective on its own, they bound the range of the objectives on the frontier.
+	anchors := make([]ParetoPoint, 0, len(objectives))
+
+	for _, o := range objectives {
+		p, err := evaluate(o, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		anchors = append(anchors, p)
+	}
+
+	constrained := objectives[1:]
+	grids := make([][]float64, 0, len(constrained))
+
+	for _, o := range constrained {
+		lower, upper := math.Inf(1), math.Inf(-1)
+
+		for _, p := range anchors {
+			lower = math.Min(lower, p.value(o))
+			upper = math.Max(upper, p.value(o))
+		}
+
+		grid := make([]float64, 0, points)
+
+		for _, fr := range FractionGrid(points) {
+			grid = append(grid, lower+(upper-lower)*fr+paretoSlack)
+		}
+
+		grids = append(grids, grid)
+	}
+
+	candidates := append([]ParetoPoint{}, anchors...)
+
+	for _, limits := range cartesianProduct(grids) {
+		l := make(map[OptimizeType]float64)
+
+		for i, o := range constrained {
+			l[o] = limits[i]
+		}
+
+		p, err := evaluate(objectives[0], l)
+
+		// Some combinations of limits are infeasible, e.g. a low latency together with a low network load.
+		if errors.Is(err, errNoOptimalStrategy) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, p)
+	}
+
+	return paretoFilter(candidates, objectives), nil
+}
+
+const (
+	// paretoThreshold is the tolerance used when comparing the metrics of two strategies.
+	paretoThreshold = 1e-6
+	// paretoSlack is added to the limits, so that the limits at the minimum of an objective stay feasible.
+	paretoSlack = 1e-9
+)
+
+// paretoPoint returns the ParetoPoint of the strategy given a read and write Distribution.
+func (s *Strategy) paretoPoint(rf *Distribution, wf *Distribution) (ParetoPoint, error) {
+	var err error
+
+	p := ParetoPoint{Strategy: s}
+
+	if p.Load, err = s.Load(rf, wf); err != nil {
+		return ParetoPoint{}, err
+	}
+
+	if p.NetworkLoad, err = s.NetworkLoad(rf, wf); err != nil {
+		return ParetoPoint{}, err
+	}
+
+	if p.Latency, err = s.Latency(rf, wf); err != nil {
+		return ParetoPoint{}, err
+	}
+
+	if p.Cost, err = s.Cost(rf, wf); err != nil {
+		return ParetoPoint{}, err
+	}
+
+	return p, nil
+}
+
+// value returns the value of the objective at the point.
+func (p ParetoPoint) value(o OptimizeType) float64 {
+	switch o {
+	case Load:
+		return p.Load
+	case Network:
+		return p.NetworkLoad
+	case Latency:
+		return p.Latency
+	case Cost:
+		return p.Cost
+	}
+
+	return 0
+}
+
+// dominates returns true if p is no worse than q on every objective and better on at least one.
+func (p ParetoPoint) dominates(q ParetoPoint, objectives []OptimizeType) bool {
+	better := false
+
+	for _, o := range objectives {
+		if p.value(o) > q.value(o)+paretoThreshold {
+			return false
+		}
+
+		if p.value(o) < q.value(o)-paretoThreshold {
+			better = true
+		}
+	}
+
+	return better
+}
+
+// paretoFilter returns the non-dominated points, without duplicates, sorted by increasing objectives.
+func paretoFilter(candidates []ParetoPoint, objectives []OptimizeType) []ParetoPoint {
+	result := make([]ParetoPoint, 0)
+
+	for i, p := range candidates {
+		dominated := false
+
+		for j, q := range candidates {
+			if i != j && q.dominates(p, objectives) {
+				dominated = true
+				break
+			}
+		}
+
+		if dominated {
+			continue
+		}
+
+		duplicate := false
+
+		for _, q := range result {
+			if p.equals(q, objectives) {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			result = append(result, p)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		for _, o := range objectives {
+			if math.Abs(result[i].value(o)-result[j].value(o)) > paretoThreshold {
+				return result[i].value(o) < result[j].value(o)
+			}
+		}
+		return false
+	})
+
+	return result
+}
+
+// equals returns true if p and q have the same value on every objective.
+func (p ParetoPoint) equals(q ParetoPoint, objectives []OptimizeType) bool {
+	for _, o := range objectives {
+		if math.Abs(p.value(o)-q.value(o)) > paretoThreshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setLimit sets the limit of the objective in the StrategyOptions.
+func setLimit(options *StrategyOptions, o OptimizeType, limit *float64) {
+	switch o {
+	case Load:
+		options.LoadLimit = limit
+	case Network:
+		options.NetworkLimit = limit
+	case Latency:
+		options.LatencyLimit = limit
+	case Cost:
+		options.CostLimit = limit
+	}
+}
+
+// cartesianProduct returns the cartesian product of the lists of values.
+func cartesianProduct(values [][]float64) [][]float64 {
+	result := [][]float64{{}}
+
+	for _, vs := range values {
+		next := make([][]float64, 0, len(result)*len(vs))
+
+		for _, prefix := range result {
+			for _, v := range vs {
+				combination := append(append([]float64{}, prefix...), v)
+				next = append(next, combination)
+			}
+		}
+
+		result = next
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParetoFrontier(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a := NewNodeWithCapacityAndLatency("a", 1, 1, 1*time.Millisecond)
+	b := NewNodeWithCapacityAndLatency("b", 1, 1, 2*time.Millisecond)
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	options := StrategyOptions{ReadFraction: QuorumDistribution{values: DistributionValues{1: 1}}}
+
+	// Reading from a with probability p gives a load of max(p, 1 - p) and a latency of 2 - p.
+	frontier, err := qs.ParetoFrontier([]OptimizeType{Load, Latency}, 3, options)
+	assert.NilError(t, err)
+
+	expected := []struct {
+		load    float64
+		latency float64
+	}{
+		{0.5, 1.5},
+		{0.75, 1.25},
+		{1, 1},
+	}
+
+	assert.Equal(t, len(frontier), len(expected))
+
+	for i, p := range frontier {
+		assert.Assert(t, math.Abs(p.Load-expected[i].load) < float64EqualityThreshold)
+		assert.Assert(t, math.Abs(p.Latency-expected[i].latency) < float64EqualityThreshold)
+		assert.Assert(t, math.Abs(p.NetworkLoad-1) < float64EqualityThreshold)
+	}
+
+	c := NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond)
+	d := NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
+	qs = NewQuorumSystemWithReads(a.Multiply(b).Add(c).Add(d))
+
+	options = StrategyOptions{ReadFraction: QuorumDistribution{values: DistributionValues{0.75: 1}}}
+	frontier, err = qs.ParetoFrontier([]OptimizeType{Latency, Network, Load}, 4, options)
+	assert.NilError(t, err)
+	assert.Assert(t, len(frontier) >= 2)
+
+	objectives := []OptimizeType{Latency, Network, Load}
+
+	for i, p := range frontier {
+		for j, q := range frontier {
+			assert.Assert(t, i == j || !q.dominates(p, objectives))
+		}
+	}
+
+	_, err = qs.ParetoFrontier([]OptimizeType{Load}, 3, options)
+	assert.Error(t, err, "two or three objectives must be given")
+
+	_, err = qs.ParetoFrontier([]OptimizeType{Load, Load}, 3, options)
+	assert.Error(t, err, "objective Load is given more than once")
+
+	_, err = qs.ParetoFrontier([]OptimizeType{Load, "Throughput"}, 3, options)
+	assert.Error(t, err, "unknown objective Throughput")
+
+	// The infeasible limits are skipped, the other errors of the Solver are reported.
+	defer SetSolver(nil)
+
+	SetSolver(&limitedSolver{solves: 2, err: ErrInfeasible})
+	frontier, err = qs.ParetoFrontier([]OptimizeType{Load, Latency}, 3, options)
+	assert.NilError(t, err)
+	assert.Equal(t, len(frontier), 2)
+
+	SetSolver(&limitedSolver{solves: 2, err: fmt.Errorf("%w of 10 pivots", ErrIterationLimit)})
+	_, err = qs.ParetoFrontier([]OptimizeType{Load, Latency}, 3, options)
+	assert.Error(t, err, "the solver reached its iteration limit of 10 pivots")
+}
+
+// limitedSolver is a Solver that solves the first programs with SimplexSolver, then fails with err.
+type limitedSolver struct {
+	solves int
+	err    error
+}
+
+func (s *limitedSolver) Solve(lp LinearProgram) (LinearProgramSolution, error) {
+	if s.solves == 0 {
+		return LinearProgramSolution{}, s.err
+	}
+
+	s.solves--
+
+	return SimplexSolver{}.Solve(lp)
+}
@@ -0,0 +1,176 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CapacityPlanOptions describes the options of a capacity plan.
+type CapacityPlanOptions struct {
+	// Throughput is the target throughput, in operations per unit of time of the node capacities.
+	Throughput float64
+	// Uniform scales the capacities of the scaled nodes by the same factor, instead of finding the minimal capacity
+	// of each node.
+	Uniform bool
+	// Nodes restricts the scaling to the nodes with the given names, the other nodes keep their capacities. Every node
+	// is scaled when empty.
+	Nodes []string
+}
+
+// NodeCapacity describes the capacities a node needs in a capacity plan.
+type NodeCapacity struct {
+	Node Node
+	// Scale is the factor applied to the current capacities of the node.
+	Scale         float64
+	ReadCapacity  float64
+	WriteCapacity float64
+}
+
+// CapacityPlan describes the capacities the nodes need to reach a target throughput.
+type CapacityPlan struct {
+	// Scale is the factor applied to the capacities of the scaled nodes, when the plan is uniform.
+	Scale float64
+	// Nodes are the capacities of each node, sorted by name.
+	Nodes []NodeCapacity
+	// Strategy is the strategy reaching the target throughput once the nodes have the planned capacities. The nodes it
+	// does not use may have a zero scale.
+	Strategy *Strategy
+}
+
+// CapacityPlan returns the minimal capacities of the nodes that make the quorum system reach the target throughput
+// at every read fraction of the workload.
+//
+// The capacities of a node are its current capacities times a scale factor s_x. The load of a node is divided by s_x,
+// so the throughput T is reached when T * load_x(fr) ≤ s_x for each node x and read fraction fr, which is linear in
+// the quorum probabilities and in s_x. The plan minimizes Σ s_x, i.e. the capacity to add expressed in units of the
+// current nodes, or the common s when Uniform. The Optimize option and the LoadLimit are ignored, the other options
+// (e.g. the limits on the network load, latency and cost, or F) apply to the strategy.
+func (qs QuorumSystem) CapacityPlan(planOptions CapacityPlanOptions,
+	strategyOptions StrategyOptions) (CapacityPlan, error) {
+	if planOptions.Throughput <= 0 {
+		return CapacityPlan{}, fmt.Errorf("the target throughput must be > 0")
+	}
+
+	scaled := make(map[Node]bool)
+
+	for _, name := range planOptions.Nodes {
+		n, ok := qs.nameToNode[name]
+
+		if !ok {
+			return CapacityPlan{}, fmt.Errorf("node %s not found", name)
+		}
+
+		scaled[n] = true
+	}
+
+	nodes := qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	if len(planOptions.Nodes) == 0 {
+		for _, n := range nodes {
+			scaled[n] = true
+		}
+	}
+
+	options := strategyOptions
+	options.Optimize = Load
+	options.LoadLimit = nil
+
+	sb, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(options))
+
+	if err != nil {
+		return CapacityPlan{}, err
+	}
+
+	problem, err := qs.buildLoadOptimalProblem(Load, rq, wq, d,
+		nil, sb.NetworkLimit, sb.LatencyLimit, sb.CostLimit, nil)
+
+	if err != nil {
+		return CapacityPlan{}, err
+	}
+
+	def := problem.def
+
+	for i := range def.Vars {
+		def.Vars[i] = 0
+	}
+
+	// One scale variable per scaled node, or a single one shared by the scaled nodes when uniform.
+	nScales := len(scaled)
+
+	if planOptions.Uniform {
+		nScales = 1
+	}
+
+	bounds := make([][2]float64, nScales)
+
+	for i := range bounds {
+		bounds[i] = [2]float64{0, math.Inf(1)}
+	}
+
+	first := def.addVariables(bounds...)
+	scaleIndex := make(map[Node]int)
+
+	for _, n := range nodes {
+		if !scaled[n] {
+			continue
+		}
+
+		if planOptions.Uniform {
+			scaleIndex[n] = first
+		} else {
+			scaleIndex[n] = first + len(scaleIndex)
+		}
+
+		def.Vars[scaleIndex[n]] = 1
+	}
+
+	// The load rows load_x(fr) - l_fr ≤ 0 become load_x(fr) - s_x / T ≤ 0, or load_x(fr) ≤ 1 / T for the nodes
+	// that are not scaled. The load variables l_fr are left unused.
+	loadVarIndex := len(problem.readQuorumVars) + len(problem.writeQuorumVars)
+
+	for i, rows := range problem.loadRows {
+		for n, r := range rows {
+			row := def.Objectives[r]
+			row[1+loadVarIndex+i] = 0
+
+			if index, ok := scaleIndex[n]; ok {
+				row[1+index] = -1 / planOptions.Throughput
+			} else {
+				row[len(row)-1] = 1 / planOptions.Throughput
+			}
+		}
+	}
+
+	solution, err := def.solve()
+
+	if errors.Is(err, errNoOptimalStrategy) {
+		return CapacityPlan{}, fmt.Errorf("the target throughput cannot be reached: %w", err)
+	}
+
+	if err != nil {
+		return CapacityPlan{}, err
+	}
+
+	strategy := problem.strategy(qs, solution)
+	plan := CapacityPlan{Scale: 1, Strategy: &strategy}
+
+	if planOptions.Uniform {
+		plan.Scale = solution.Primal[first]
+	}
+
+	for _, n := range nodes {
+		scale := 1.0
+
+		if index, ok := scaleIndex[n]; ok {
+			scale = solution.Primal[index]
+		}
+
+		plan.Nodes = append(plan.Nodes, NodeCapacity{Node: n, Scale: scale, ReadCapacity: scale * n.GetReadCapacity(),
+			WriteCapacity: scale * n.GetWriteCapacity()})
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestCapacityPlan(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b, c := NewNode("a"), NewNode("b"), NewNode("c")
+	majority, err := NewChoose(2, []Expr{a, b, c})
+	assert.NilError(t, err)
+
+	qs := NewQuorumSystemWithReads(majority)
+
+	strategyOptions := StrategyOptions{
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}
+
+	// The optimal load is 2/3, the capacity 1.5: reaching 3 doubles every node.
+	plan, err := qs.CapacityPlan(CapacityPlanOptions{Throughput: 3, Uniform: true}, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(plan.Scale-2) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", plan.Scale))
+	assert.Equal(t, len(plan.Nodes), 3)
+
+	for _, n := range plan.Nodes {
+		assert.Assert(t, math.Abs(n.ReadCapacity-2) < float64EqualityThreshold)
+	}
+
+	load, err := plan.Strategy.Load(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-2.0/3) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", load))
+
+	// Every read touches two nodes, so 3 reads need 6 units of capacity, however they are spread.
+	plan, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 3}, strategyOptions)
+	assert.NilError(t, err)
+
+	total := 0.0
+
+	for _, n := range plan.Nodes {
+		total += n.Scale
+	}
+
+	assert.Assert(t, math.Abs(total-6) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", total))
+
+	// Scaling only a: b and c serve at most half of the reads each, so a serves all of them.
+	plan, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 2, Nodes: []string{"a"}}, strategyOptions)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.Nodes[0].Node.Name, "a")
+	assert.Assert(t, math.Abs(plan.Nodes[0].Scale-2) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(plan.Nodes[1].Scale-1) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(plan.Nodes[2].WriteCapacity-1) < float64EqualityThreshold)
+
+	plan, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 1.5, Nodes: []string{"a"}}, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(plan.Nodes[0].Scale-1) < float64EqualityThreshold)
+
+	_, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 3, Nodes: []string{"a"}}, strategyOptions)
+	assert.Error(t, err, "the target throughput cannot be reached: no optimal strategy found: the linear program is infeasible")
+	assert.Assert(t, errors.Is(err, errNoOptimalStrategy))
+
+	// The other errors of the Solver are not reported as an unreachable throughput.
+	SetSolver(failingSolver{err: fmt.Errorf("%w of 10 pivots", ErrIterationLimit)})
+	_, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 1.5}, strategyOptions)
+	SetSolver(nil)
+	assert.Error(t, err, "the solver reached its iteration limit of 10 pivots")
+
+	_, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 0}, strategyOptions)
+	assert.Error(t, err, "the target throughput must be > 0")
+
+	_, err = qs.CapacityPlan(CapacityPlanOptions{Throughput: 1, Nodes: []string{"x"}}, strategyOptions)
+	assert.Error(t, err, "node x not found")
+}
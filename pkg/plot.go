@@ -0,0 +1,364 @@
+package pkg
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SweepMetric describes a metric of a Sweep that can be plotted.
+type SweepMetric string
+
+const (
+	SweepLoad        SweepMetric = "Load"
+	SweepCapacity    SweepMetric = "Capacity"
+	SweepNetworkLoad SweepMetric = "Network load"
+	SweepLatency     SweepMetric = "Latency"
+)
+
+const (
+	chartWidth        = 640.0
+	chartHeight       = 400.0
+	chartMarginLeft   = 70.0
+	chartMarginRight  = 140.0
+	chartMarginTop    = 40.0
+	chartMarginBottom = 50.0
+	chartTicks        = 5
+)
+
+// chartColors is the palette of the series, reused cyclically.
+var chartColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f"}
+
+// chartSeries describes a labeled series of values.
+type chartSeries struct {
+	label  string
+	values []float64
+}
+
+// Series returns the values of the metric, aligned on ReadFractions.
+func (sw Sweep) Series(metric SweepMetric) ([]float64, error) {
+	switch metric {
+	case SweepLoad:
+		return sw.Load, nil
+	case SweepCapacity:
+		return sw.Capacity, nil
+	case SweepNetworkLoad:
+		return sw.NetworkLoad, nil
+	case SweepLatency:
+		return sw.Latency, nil
+	}
+
+	return nil, fmt.Errorf("unknown sweep metric %s", metric)
+}
+
+// PlotSweeps writes an SVG line chart of the metric against the read fraction, with one curve per sweep.
+// The sweeps are indexed by the label shown in the legend.
+func PlotSweeps(w io.Writer, metric SweepMetric, sweeps map[string]Sweep) error {
+	if len(sweeps) == 0 {
+		return fmt.Errorf("at least one sweep must be given")
+	}
+
+	labels := make([]string, 0, len(sweeps))
+
+	for label := range sweeps {
+		labels = append(labels, label)
+	}
+
+	sort.Strings(labels)
+
+	xs := make([][]float64, 0, len(labels))
+	series := make([]chartSeries, 0, len(labels))
+
+	for _, label := range labels {
+		values, err := sweeps[label].Series(metric)
+
+		if err != nil {
+			return err
+		}
+
+		xs = append(xs, sweeps[label].ReadFractions)
+		series = append(series, chartSeries{label: label, values: values})
+	}
+
+	return writeLineChart(w, fmt.Sprintf("%s by read fraction", metric), "Read fraction", string(metric), xs, series)
+}
+
+// PlotNodeLoad writes an SVG bar chart of the load of each node given a read and write Distribution, stacking the
+// load due to the reads and the load due to the writes.
+func (s Strategy) PlotNodeLoad(w io.Writer, rf *Distribution, wf *Distribution) error {
+	return s.plotNodes(w, "Node load", rf, wf, s.getNodeReadWriteLoad)
+}
+
+// PlotNodeUtilization writes an SVG bar chart of the utilization of each node given a read and write Distribution,
+// stacking the utilization due to the reads and the utilization due to the writes.
+func (s Strategy) PlotNodeUtilization(w io.Writer, rf *Distribution, wf *Distribution) error {
+	return s.plotNodes(w, "Node utilization", rf, wf, s.getNodeReadWriteUtilization)
+}
+
+// PlotNodeThroughput writes an SVG bar chart of the throughput of each node given a read and write Distribution,
+// stacking the reads and the writes processed by the node.
+func (s Strategy) PlotNodeThroughput(w io.Writer, rf *Distribution, wf *Distribution) error {
+	return s.plotNodes(w, "Node throughput", rf, wf, s.getNodeReadWriteThroughput)
+}
+
+// plotNodes writes a stacked bar chart of the read and write parts of a per-node metric, sorted by node name.
+func (s Strategy) plotNodes(w io.Writer, title string, rf *Distribution, wf *Distribution,
+	metric func(n Node, fr float64) (float64, float64)) error {
+	d, err := canonicalizeReadsWrites(rf, wf)
+
+	if err != nil {
+		return err
+	}
+
+	nodes := s.Qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	names := make([]string, 0, len(nodes))
+	reads := chartSeries{label: "Reads", values: make([]float64, 0, len(nodes))}
+	writes := chartSeries{label: "Writes", values: make([]float64, 0, len(nodes))}
+
+	for _, n := range nodes {
+		nodeReads, nodeWrites := 0.0, 0.0
+
+		for fr, p := range d {
+			r, w := metric(n, fr)
+			nodeReads += p * r
+			nodeWrites += p * w
+		}
+
+		names = append(names, n.Name)
+		reads.values = append(reads.values, nodeReads)
+		writes.values = append(writes.values, nodeWrites)
+	}
+
+	return writeStackedBarChart(w, title, title, names, []chartSeries{reads, writes})
+}
+
+// writeLineChart writes an SVG chart with one line for each series, series[i] is plotted against xs[i].
+// Non-finite values are left out of the lines.
+func writeLineChart(w io.Writer, title string, xLabel string, yLabel string, xs [][]float64,
+	series []chartSeries) error {
+	xMin, xMax := math.Inf(1), math.Inf(-1)
+	yMax := 0.0
+
+	for i, s := range series {
+		for j, v := range s.values {
+			if isFinite(v) {
+				yMax = math.Max(yMax, v)
+				xMin = math.Min(xMin, xs[i][j])
+				xMax = math.Max(xMax, xs[i][j])
+			}
+		}
+	}
+
+	if xMin > xMax {
+		return fmt.Errorf("there are no finite values to plot")
+	}
+
+	if xMin == xMax {
+		xMin, xMax = xMin-0.5, xMax+0.5
+	}
+
+	yMax = niceCeil(yMax)
+
+	var sb strings.Builder
+
+	writeChartFrame(&sb, title, yLabel, yMax)
+
+	// x axis ticks and label.
+	for i := 0; i <= chartTicks; i++ {
+		x := xMin + (xMax-xMin)*float64(i)/chartTicks
+		px := chartMarginLeft + plotWidth()*float64(i)/chartTicks
+
+		fmt.Fprintf(&sb, `<text x="%s" y="%s" text-anchor="middle" font-size="11">%s</text>`+"\n",
+			formatCoordinate(px), formatCoordinate(chartHeight-chartMarginBottom+16), formatValue(x))
+	}
+
+	fmt.Fprintf(&sb, `<text x="%s" y="%s" text-anchor="middle" font-size="12">%s</text>`+"\n",
+		formatCoordinate(chartMarginLeft+plotWidth()/2), formatCoordinate(chartHeight-10), html.EscapeString(xLabel))
+
+	for i, s := range series {
+		color := chartColors[i%len(chartColors)]
+		points := make([]string, 0)
+
+		flush := func() {
+			if len(points) > 0 {
+				fmt.Fprintf(&sb, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`+"\n",
+					color, strings.Join(points, " "))
+			}
+			points = points[:0]
+		}
+
+		for j, v := range s.values {
+			if !isFinite(v) {
+				flush()
+				continue
+			}
+
+			px := chartMarginLeft + plotWidth()*(xs[i][j]-xMin)/(xMax-xMin)
+			py := chartMarginTop + plotHeight()*(1-v/yMax)
+			points = append(points, formatCoordinate(px)+","+formatCoordinate(py))
+		}
+
+		flush()
+		writeLegendEntry(&sb, i, s.label)
+	}
+
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// writeStackedBarChart writes an SVG chart with one bar per category, stacking the values of the series.
+// Non-finite values are left out of the bars.
+func writeStackedBarChart(w io.Writer, title string, yLabel string, categories []string, series []chartSeries) error {
+	if len(categories) == 0 {
+		return fmt.Errorf("there are no values to plot")
+	}
+
+	yMax := 0.0
+
+	for j := range categories {
+		total := 0.0
+
+		for _, s := range series {
+			if isFinite(s.values[j]) {
+				total += s.values[j]
+			}
+		}
+
+		yMax = math.Max(yMax, total)
+	}
+
+	yMax = niceCeil(yMax)
+
+	var sb strings.Builder
+
+	writeChartFrame(&sb, title, yLabel, yMax)
+
+	slot := plotWidth() / float64(len(categories))
+	barWidth := slot * 0.6
+
+	for j, category := range categories {
+		px := chartMarginLeft + slot*float64(j) + (slot-barWidth)/2
+		base := 0.0
+
+		for i, s := range series {
+			if !isFinite(s.values[j]) {
+				continue
+			}
+
+			top := base + s.values[j]
+
+			fmt.Fprintf(&sb, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+				formatCoordinate(px), formatCoordinate(chartMarginTop+plotHeight()*(1-top/yMax)),
+				formatCoordinate(barWidth), formatCoordinate(plotHeight()*s.values[j]/yMax),
+				chartColors[i%len(chartColors)])
+
+			base = top
+		}
+
+		fmt.Fprintf(&sb, `<text x="%s" y="%s" text-anchor="middle" font-size="11">%s</text>`+"\n",
+			formatCoordinate(px+barWidth/2), formatCoordinate(chartHeight-chartMarginBottom+16),
+			html.EscapeString(category))
+	}
+
+	for i, s := range series {
+		writeLegendEntry(&sb, i, s.label)
+	}
+
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// writeChartFrame writes the SVG header, the title, and the y axis from 0 to yMax with its grid lines.
+func writeChartFrame(sb *strings.Builder, title string, yLabel string, yMax float64) {
+	fmt.Fprintf(sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="sans-serif">`+"\n",
+		formatCoordinate(chartWidth), formatCoordinate(chartHeight), formatCoordinate(chartWidth), formatCoordinate(chartHeight))
+	fmt.Fprintf(sb, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(sb, `<text x="%s" y="24" text-anchor="middle" font-size="14">%s</text>`+"\n",
+		formatCoordinate(chartMarginLeft+plotWidth()/2), html.EscapeString(title))
+
+	for i := 0; i <= chartTicks; i++ {
+		y := yMax * float64(i) / chartTicks
+		py := chartMarginTop + plotHeight()*(1-float64(i)/chartTicks)
+
+		fmt.Fprintf(sb, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="#dddddd"/>`+"\n",
+			formatCoordinate(chartMarginLeft), formatCoordinate(py), formatCoordinate(chartMarginLeft+plotWidth()),
+			formatCoordinate(py))
+		fmt.Fprintf(sb, `<text x="%s" y="%s" text-anchor="end" font-size="11">%s</text>`+"\n",
+			formatCoordinate(chartMarginLeft-6), formatCoordinate(py+4), formatValue(y))
+	}
+
+	fmt.Fprintf(sb, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="black"/>`+"\n",
+		formatCoordinate(chartMarginLeft), formatCoordinate(chartMarginTop), formatCoordinate(chartMarginLeft),
+		formatCoordinate(chartMarginTop+plotHeight()))
+	fmt.Fprintf(sb, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="black"/>`+"\n",
+		formatCoordinate(chartMarginLeft), formatCoordinate(chartMarginTop+plotHeight()),
+		formatCoordinate(chartMarginLeft+plotWidth()), formatCoordinate(chartMarginTop+plotHeight()))
+	fmt.Fprintf(sb, `<text x="16" y="%s" text-anchor="middle" font-size="12" transform="rotate(-90 16 %s)">%s</text>`+"\n",
+		formatCoordinate(chartMarginTop+plotHeight()/2), formatCoordinate(chartMarginTop+plotHeight()/2),
+		html.EscapeString(yLabel))
+}
+
+// writeLegendEntry writes the legend entry of the i-th series, on the right of the plot.
+func writeLegendEntry(sb *strings.Builder, i int, label string) {
+	px := chartWidth - chartMarginRight + 16
+	py := chartMarginTop + 20*float64(i)
+
+	fmt.Fprintf(sb, `<rect x="%s" y="%s" width="12" height="12" fill="%s"/>`+"\n",
+		formatCoordinate(px), formatCoordinate(py), chartColors[i%len(chartColors)])
+	fmt.Fprintf(sb, `<text x="%s" y="%s" font-size="12">%s</text>`+"\n",
+		formatCoordinate(px+18), formatCoordinate(py+10), html.EscapeString(label))
+}
+
+// plotWidth returns the width of the plotting area.
+func plotWidth() float64 {
+	return chartWidth - chartMarginLeft - chartMarginRight
+}
+
+// plotHeight returns the height of the plotting area.
+func plotHeight() float64 {
+	return chartHeight - chartMarginTop - chartMarginBottom
+}
+
+// niceCeil returns the smallest 1, 2 or 5 times a power of 10 greater than or equal to v, or 1 when v is 0.
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*magnitude >= v*(1-1e-12) {
+			return m * magnitude
+		}
+	}
+
+	return 10 * magnitude
+}
+
+// isFinite returns true if v is neither infinite nor NaN.
+func isFinite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
+// formatCoordinate formats an SVG coordinate with at most two decimals.
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// formatValue formats an axis value with at most 4 significant digits.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
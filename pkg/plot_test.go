@@ -0,0 +1,135 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"gotest.tools/assert"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// svgElements parses the SVG document and counts its elements by name.
+func svgElements(t *testing.T, svg string) map[string]int {
+	elements := make(map[string]int)
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+
+	for {
+		token, err := decoder.Token()
+
+		if err == io.EOF {
+			return elements
+		}
+
+		assert.NilError(t, err)
+
+		if start, ok := token.(xml.StartElement); ok {
+			elements[start.Name.Local]++
+		}
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected float64
+	}{
+		{0, 1},
+		{0.75, 1},
+		{1, 1},
+		{1.2, 2},
+		{3, 5},
+		{7, 10},
+		{0.03, 0.05},
+	}
+
+	for _, tt := range tests {
+		assert.Assert(t, niceCeil(tt.value) > tt.expected*(1-1e-9) && niceCeil(tt.value) < tt.expected*(1+1e-9))
+	}
+}
+
+func TestPlotSweeps(t *testing.T) {
+	a := NewNodeWithCapacityAndLatency("a", 1, 1, 1*time.Millisecond)
+	b := NewNodeWithCapacityAndLatency("b", 1, 1, 2*time.Millisecond)
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	loadSweep, err := qs.Sweep(FractionGrid(5), StrategyOptions{Optimize: Load})
+	assert.NilError(t, err)
+
+	latencySweep, err := qs.Sweep(FractionGrid(5), StrategyOptions{Optimize: Latency})
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	err = PlotSweeps(&buf, SweepLoad, map[string]Sweep{"load optimal": loadSweep, "latency <optimal>": latencySweep})
+	assert.NilError(t, err)
+
+	elements := svgElements(t, buf.String())
+	assert.Equal(t, elements["svg"], 1)
+	assert.Equal(t, elements["polyline"], 2)
+	assert.Assert(t, strings.Contains(buf.String(), "latency &lt;optimal&gt;"))
+	assert.Assert(t, strings.Contains(buf.String(), "Load by read fraction"))
+
+	err = PlotSweeps(&buf, SweepMetric("Throughput"), map[string]Sweep{"load optimal": loadSweep})
+	assert.Error(t, err, "unknown sweep metric Throughput")
+
+	err = PlotSweeps(&buf, SweepLoad, map[string]Sweep{})
+	assert.Error(t, err, "at least one sweep must be given")
+}
+
+func TestPlotNodes(t *testing.T) {
+	a, b, c := NewNodeWithCapacity("a", 2, 1), NewNodeWithCapacity("b", 2, 1), NewNodeWithCapacity("c", 2, 1)
+	qs := NewQuorumSystemWithReads(a.Add(b).Add(c))
+
+	strategy, err := qs.Strategy(initializeStrategyOptions(StrategyOptions{
+		Optimize:     Load,
+		ReadFraction: QuorumDistribution{values: DistributionValues{0.5: 1}},
+	}))
+	assert.NilError(t, err)
+
+	var rf Distribution = QuorumDistribution{values: DistributionValues{0.5: 1}}
+	var wf Distribution
+
+	plots := []func(w io.Writer, rf *Distribution, wf *Distribution) error{
+		strategy.PlotNodeLoad, strategy.PlotNodeUtilization, strategy.PlotNodeThroughput,
+	}
+
+	for _, plot := range plots {
+		var buf bytes.Buffer
+		assert.NilError(t, plot(&buf, &rf, &wf))
+
+		elements := svgElements(t, buf.String())
+
+		// A background, 2 stacked bars for each of the 3 nodes and 2 legend entries.
+		assert.Equal(t, elements["rect"], 1+2*3+2)
+
+		for _, name := range []string{">a<", ">b<", ">c<", ">Reads<", ">Writes<"} {
+			assert.Assert(t, strings.Contains(buf.String(), name))
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.Error(t, strategy.PlotNodeLoad(&buf, &wf, &wf), "either readFraction or writeFraction must be given")
+
+	// Nodes of infinite capacity are never loaded: the utilization is 0 and the infinite throughput is not plotted.
+	x, y := NewNodeWithCapacity("x", math.Inf(1), math.Inf(1)), NewNodeWithCapacity("y", math.Inf(1), math.Inf(1))
+	unloaded := NewStrategy(NewQuorumSystemWithReads(x.Add(y)),
+		Sigma{Values: []SigmaRecord{{Quorum: ExprSet{x: true}, Probability: 1}}},
+		Sigma{Values: []SigmaRecord{{Quorum: ExprSet{x: true, y: true}, Probability: 1}}})
+
+	utilization, err := unloaded.NodeUtilization(x, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Equal(t, *utilization, 0.0)
+
+	throughput, err := unloaded.NodeThroughput(x, &rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.IsInf(*throughput, 1))
+
+	buf.Reset()
+	assert.NilError(t, unloaded.PlotNodeThroughput(&buf, &rf, &wf))
+	assert.Assert(t, !strings.Contains(buf.String(), "NaN") && !strings.Contains(buf.String(), "Inf"))
+
+	// A background, the empty read bar of y and 2 legend entries, the infinite bars are left out.
+	assert.Equal(t, svgElements(t, buf.String())["rect"], 1+1+2)
+}
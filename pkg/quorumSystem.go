@@ -1,10 +1,12 @@
 package pkg
 
 import (
+	"errors"
 	"fmt"
-	"github.com/lanl/clp"
 	"math"
 	"sort"
+	"strings"
+	"time"
 )
 
 // nameToNode keeps track of the name to node mapping ( "a"-> Node("a")).
@@ -60,6 +62,26 @@ func newDefinitionWithVarsAndConstraints(vars ...[]lpVariable) lpDefinition {
 	return def
 }
 
+// addVariables appends variables with the given bounds and a zero objective coefficient to the lpDefinition, the
+// existing rows get a zero coefficient for them. It returns the index of the first new variable.
+func (def *lpDefinition) addVariables(bounds ...[2]float64) int {
+	index := len(def.Vars)
+
+	for _, b := range bounds {
+		def.Vars = append(def.Vars, 0)
+		def.Constraints = append(def.Constraints, b)
+	}
+
+	for i, row := range def.Objectives {
+		padded := make([]float64, 0, len(row)+len(bounds))
+		padded = append(padded, row[:len(row)-1]...)
+		padded = append(padded, make([]float64, len(bounds))...)
+		def.Objectives[i] = append(padded, row[len(row)-1])
+	}
+
+	return index
+}
+
 // QuorumSystem describes a read-write quorum system.
 type QuorumSystem struct {
 	// reads describes the read-quorum.
@@ -68,6 +90,8 @@ type QuorumSystem struct {
 	writes Expr
 	// nameToNode keeps track the name of a node to a GetNodeByName.
 	nameToNode nameToNode
+	// payloadSizes describes the size of the messages of the operations, nil stands for DefaultPayloadSizes.
+	payloadSizes *PayloadSizes
 }
 
 // NewQuorumSystem defines a new quorum system given the reads Expr and the writes Expr.
@@ -257,6 +281,54 @@ func (qs QuorumSystem) WriteResilience() uint {
 	return qs.writes.Resilience()
 }
 
+// MinimalReadFailureSets returns the minimal sets of nodes whose failure leaves no read quorum available,
+// i.e. the minimal transversals of the read quorums.
+// maxSize bounds the size of the returned sets, 0 means no bound.
+func (qs QuorumSystem) MinimalReadFailureSets(maxSize uint) []ExprSet {
+	return qs.minimalTransversals(qs.ListReadQuorums(), maxSize)
+}
+
+// MinimalWriteFailureSets returns the minimal sets of nodes whose failure leaves no write quorum available,
+// i.e. the minimal transversals of the write quorums.
+// maxSize bounds the size of the returned sets, 0 means no bound.
+func (qs QuorumSystem) MinimalWriteFailureSets(maxSize uint) []ExprSet {
+	return qs.minimalTransversals(qs.ListWriteQuorums(), maxSize)
+}
+
+// SmallestReadFailureSet returns one of the smallest sets of nodes whose failure leaves no read quorum available.
+// Its size is ReadResilience() + 1.
+func (qs QuorumSystem) SmallestReadFailureSet() (ExprSet, error) {
+	return minimumHittingSet(qs.ListReadQuorums())
+}
+
+// SmallestWriteFailureSet returns one of the smallest sets of nodes whose failure leaves no write quorum available.
+// Its size is WriteResilience() + 1.
+func (qs QuorumSystem) SmallestWriteFailureSet() (ExprSet, error) {
+	return minimumHittingSet(qs.ListWriteQuorums())
+}
+
+// SmallestFailureSet returns one of the smallest sets of nodes whose failure leaves either no read quorum or
+// no write quorum available. Its size is Resilience() + 1.
+func (qs QuorumSystem) SmallestFailureSet() (ExprSet, error) {
+	readFailure, err := qs.SmallestReadFailureSet()
+
+	if err != nil {
+		return nil, err
+	}
+
+	writeFailure, err := qs.SmallestWriteFailureSet()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(writeFailure) < len(readFailure) {
+		return writeFailure, nil
+	}
+
+	return readFailure, nil
+}
+
 // DupFree returns true if the quorum system is duplicate free, otherwise false.
 func (qs QuorumSystem) DupFree() bool {
 	return qs.reads.DupFree() && qs.writes.DupFree()
@@ -264,16 +336,150 @@ func (qs QuorumSystem) DupFree() bool {
 
 // Strategy returns the optimal Strategy for the given quorum system.
 func (qs QuorumSystem) Strategy(opts ...func(options *StrategyOptions) error) (*Strategy, error) {
+	sb, rq, wq, d, err := qs.prepareStrategy(opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return qs.optimalStrategy(sb, rq, wq, d)
+}
+
+// optimalStrategy returns the optimal Strategy over the given quorums, with the StrategyOptions returned by
+// prepareStrategy.
+func (qs QuorumSystem) optimalStrategy(sb *StrategyOptions, rq []ExprSet, wq []ExprSet,
+	d map[Fraction]Probability) (*Strategy, error) {
+	if sb.Optimize == TailLatency {
+		return qs.tailLatencyStrategy(sb, rq, wq, d)
+	}
+
+	weights, err := qs.normalizedWeights(sb, rq, wq, d)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return qs.loadOptimalStrategy(sb.Optimize, rq, wq, d,
+		sb.LoadLimit, sb.NetworkLimit, sb.LatencyLimit, sb.CostLimit, weights)
+}
+
+// normalizedWeights returns the weights of the Weighted optimization divided by the optimal value of their metric
+// under the same limits, or nil when not optimizing for Weighted.
+func (qs QuorumSystem) normalizedWeights(sb *StrategyOptions, readQuorums []ExprSet, writeQuorums []ExprSet,
+	readFraction DistributionValues) (*ObjectiveWeights, error) {
+	if sb.Optimize != Weighted {
+		return nil, nil
+	}
+
+	normalize := func(weight float64, optimize OptimizeType) (float64, error) {
+		if weight == 0 {
+			return 0, nil
+		}
+
+		problem, err := qs.buildLoadOptimalProblem(optimize, readQuorums, writeQuorums, readFraction,
+			sb.LoadLimit, sb.NetworkLimit, sb.LatencyLimit, sb.CostLimit, nil)
+
+		if err != nil {
+			return 0, err
+		}
+
+		solution, err := problem.def.solve()
+
+		if err != nil {
+			return 0, err
+		}
+
+		// A metric whose optimal value is 0 is left as it is.
+		if solution.Objective <= 1e-9 {
+			return weight, nil
+		}
+
+		return weight / solution.Objective, nil
+	}
+
+	var weights ObjectiveWeights
+	var err error
+
+	if weights.Load, err = normalize(sb.Weights.Load, Load); err != nil {
+		return nil, err
+	}
+
+	if weights.Network, err = normalize(sb.Weights.Network, Network); err != nil {
+		return nil, err
+	}
+
+	if weights.Latency, err = normalize(sb.Weights.Latency, Latency); err != nil {
+		return nil, err
+	}
+
+	if weights.Cost, err = normalize(sb.Weights.Cost, Cost); err != nil {
+		return nil, err
+	}
+
+	return &weights, nil
+}
+
+// prepareStrategy validates the StrategyOptions and returns them with the read and write quorums satisfying the
+// resilience targets, and the canonical read fraction distribution.
+func (qs QuorumSystem) prepareStrategy(opts ...func(options *StrategyOptions) error) (
+	*StrategyOptions, []ExprSet, []ExprSet, map[Fraction]Probability, error) {
 
 	sb := &StrategyOptions{}
 	// ... (write initializations with default values)...
 	for _, op := range opts {
 		err := op(sb)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, nil, err
 		}
 	}
 
+	d, err := validateStrategyOptions(sb, qs.GetNodes())
+
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	rq := qs.ListReadQuorums()
+	wq := qs.ListWriteQuorums()
+
+	// no resilience target
+	if sb.F == 0 && sb.D == 0 && sb.MinDomains == 0 {
+		return sb, rq, wq, d, nil
+	}
+
+	xs := qs.GetNodesAsArray()
+
+	if sb.D == 0 && sb.MinDomains == 0 {
+		rq = qs.getResilientQuorums(sb.F, xs, qs.reads)
+		wq = qs.getResilientQuorums(sb.F, xs, qs.writes)
+
+		if len(rq) == 0 || len(wq) == 0 {
+			return nil, nil, nil, nil, fmt.Errorf("there are no %d-resilient read quorums", sb.F)
+		}
+
+		return sb, rq, wq, d, nil
+	}
+
+	nodeToDomain, err := qs.nodeDomains(sb.DomainLevel)
+
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	rq = qs.getDomainResilientQuorums(sb.F, sb.D, sb.MinDomains, nodeToDomain, xs, qs.reads)
+	wq = qs.getDomainResilientQuorums(sb.F, sb.D, sb.MinDomains, nodeToDomain, xs, qs.writes)
+
+	if len(rq) == 0 || len(wq) == 0 {
+		return nil, nil, nil, nil, fmt.Errorf("there are no quorums that survive %d %s failures spanning at least %d domains",
+			sb.D, sb.DomainLevel, sb.MinDomains)
+	}
+
+	return sb, rq, wq, d, nil
+}
+
+// validateStrategyOptions validates the StrategyOptions over the given nodes, sets the default percentile and returns
+// the canonical read fraction distribution.
+func validateStrategyOptions(sb *StrategyOptions, nodes NodeSet) (map[Fraction]Probability, error) {
 	if sb.Optimize == Load && sb.LoadLimit != nil {
 		return nil, fmt.Errorf("a getLoadObjective limit cannot be set when optimizing for getLoadObjective")
 	}
@@ -286,44 +492,74 @@ func (qs QuorumSystem) Strategy(opts ...func(options *StrategyOptions) error) (*
 		return nil, fmt.Errorf("a latency limit cannot be set when optimizing for latency")
 	}
 
+	if sb.Optimize == Cost && sb.CostLimit != nil {
+		return nil, fmt.Errorf("a cost limit cannot be set when optimizing for cost")
+	}
+
 	if sb.F < 0 {
 		return nil, fmt.Errorf("f must be >= 0")
 	}
 
-	rq := qs.ListReadQuorums()
-	wq := qs.ListWriteQuorums()
+	for n := range nodes {
+		if n.GetReadCapacity() <= 0 || n.GetWriteCapacity() <= 0 {
+			return nil, fmt.Errorf("the capacities of node %s must be > 0", n.Name)
+		}
 
-	d, err := canonicalizeReadsWrites(&sb.ReadFraction, &sb.WriteFraction)
+		if n.GetLatency() < 0 {
+			return nil, fmt.Errorf("the latency of node %s must be >= 0", n.Name)
+		}
 
-	if err != nil {
-		return nil, err
+		if n.GetLinkCost() < 0 {
+			return nil, fmt.Errorf("the link cost of node %s must be >= 0", n.Name)
+		}
+
+		if n.GetReadCost() < 0 || n.GetWriteCost() < 0 {
+			return nil, fmt.Errorf("the costs of node %s must be >= 0", n.Name)
+		}
 	}
 
-	// no resilience target
-	if sb.F == 0 {
-		return qs.loadOptimalStrategy(sb.Optimize, rq, wq, d,
-			sb.LoadLimit, sb.NetworkLimit, sb.LatencyLimit)
+	if sb.Optimize == Weighted {
+		if sb.Weights.Load < 0 || sb.Weights.Network < 0 || sb.Weights.Latency < 0 || sb.Weights.Cost < 0 {
+			return nil, fmt.Errorf("weights must be >= 0")
+		}
+
+		if sb.Weights.Load == 0 && sb.Weights.Network == 0 && sb.Weights.Latency == 0 &&
+			sb.Weights.Cost == 0 {
+			return nil, fmt.Errorf("at least one weight must be > 0 when optimizing for weighted")
+		}
 	}
 
-	xs := qs.GetNodesAsArray()
+	if sb.Optimize == TailLatency {
+		if sb.Percentile == 0 {
+			sb.Percentile = defaultPercentile
+		}
+
+		if sb.Percentile < 0 || sb.Percentile >= 1 {
+			return nil, fmt.Errorf("percentile must be in (0, 1)")
+		}
+	}
 
-	rq = make([]ExprSet, 0)
-	wq = make([]ExprSet, 0)
+	d, err := canonicalizeReadsWrites(&sb.ReadFraction, &sb.WriteFraction)
 
-	for _, e := range qs.getResilientQuorums(sb.F, xs, qs.reads) {
-		rq = append(rq, e)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, e := range qs.getResilientQuorums(sb.F, xs, qs.writes) {
-		wq = append(wq, e)
+	if sb.D == 0 && sb.MinDomains == 0 {
+		return d, nil
 	}
 
-	if len(rq) == 0 || len(wq) == 0 {
-		return nil, fmt.Errorf("there are no %d-resilient read quorums", sb.F)
+	if sb.DomainLevel == "" {
+		return nil, fmt.Errorf("a failure domain level must be set when D or MinDomains are set")
 	}
 
-	return qs.loadOptimalStrategy(sb.Optimize, rq, wq, d,
-		sb.LoadLimit, sb.NetworkLimit, sb.LatencyLimit)
+	for n := range nodes {
+		if _, ok := n.Domain(sb.DomainLevel); !ok {
+			return nil, fmt.Errorf("node %s has no failure domain at level %s", n.Name, sb.DomainLevel)
+		}
+	}
+
+	return d, nil
 }
 
 // UniformStrategy returns the standard majority quorum strategy for the quorum system.
@@ -456,54 +692,113 @@ func (qs QuorumSystem) minimize(sets []ExprSet) []ExprSet {
 	return minimalElements
 }
 
-// getResilientQuorums returns a set of quorums that has a resilience f.
-func (qs QuorumSystem) getResilientQuorums(f uint, n []Node, e Expr) []ExprSet {
-	cur := ExprSet{}
-	result := make([]ExprSet, 0)
-	return getResilientQuorumsHelper(result, f, n, e, cur, 0)
-}
+// minimalTransversals returns the minimal sets of nodes intersecting every set in sets, sorted by size and name.
+// The transversals are built incrementally with Berge's algorithm, dropping the candidates bigger than maxSize
+// (0 means no bound). Every minimal transversal is built from smaller intermediate transversals, so the bound does
+// not hide any of the minimal transversals within it.
+func (qs QuorumSystem) minimalTransversals(sets []ExprSet, maxSize uint) []ExprSet {
+	transversals := []ExprSet{{}}
 
-func getResilientQuorumsHelper(exprSets []ExprSet, minResilience uint, n []Node, e Quorum, cur ExprSet, i int) []ExprSet {
-	minf := minResilience
+	for _, set := range qs.minimize(sets) {
+		candidates := make([]ExprSet, 0)
 
-	if minResilience > uint(len(cur)) {
-		minf = uint(len(cur))
-	}
+		for _, t := range transversals {
+			if intersects(t, set) {
+				candidates = append(candidates, t)
+				continue
+			}
 
-	isQuorum := true
-	combinationSets := combinations(setToArr(cur), minf)
+			if maxSize > 0 && uint(len(t)) >= maxSize {
+				continue
+			}
 
-	for _, failure := range combinationSets {
-		if !e.IsQuorum(remove(cur, failure...)) {
-			isQuorum = false
+			for e := range set {
+				candidate := copySet(t)
+				candidate[e] = true
+				candidates = append(candidates, candidate)
+			}
 		}
+
+		transversals = qs.minimize(candidates)
 	}
 
-	if isQuorum && len(combinationSets) > 0 {
-		exprSets = append(exprSets, cur)
-		return exprSets
+	sort.Slice(transversals, func(i, j int) bool {
+		if len(transversals[i]) != len(transversals[j]) {
+			return len(transversals[i]) < len(transversals[j])
+		}
+		return setKey(transversals[i]) < setKey(transversals[j])
+	})
+
+	return transversals
+}
+
+// getResilientQuorums returns the quorums that have a resilience f, i.e. that are still quorums of e after the failure
+// of any f of their nodes. Every branch of the search is explored, so that no resilient quorum is missed.
+func (qs QuorumSystem) getResilientQuorums(f uint, n []Node, e Expr) []ExprSet {
+	return getQuorumsWhere(n, func(set ExprSet) bool {
+		return isResilientQuorum(f, set, e)
+	})
+}
+
+// getDomainResilientQuorums returns a set of quorums that has a resilience f, is still a quorum after the failure
+// of any d failure domains and spans at least minDomains failure domains.
+func (qs QuorumSystem) getDomainResilientQuorums(f uint, d uint, minDomains uint, nodeToDomain map[Node]string,
+	n []Node, e Expr) []ExprSet {
+	return getQuorumsWhere(n, func(set ExprSet) bool {
+		return isResilientQuorum(f, set, e) &&
+			isDomainResilientQuorum(d, minDomains, nodeToDomain, set, e)
+	})
+}
+
+// getQuorumsWhere returns the sets of nodes satisfying isValid, built by adding the nodes in n one at a time and
+// stopping as soon as the set is valid. isValid must be monotone: a superset of a valid set is valid too.
+func getQuorumsWhere(n []Node, isValid func(set ExprSet) bool) []ExprSet {
+	result := make([]ExprSet, 0)
+	return getQuorumsWhereHelper(result, n, isValid, ExprSet{}, 0)
+}
+
+func getQuorumsWhereHelper(exprSets []ExprSet, n []Node, isValid func(set ExprSet) bool, cur ExprSet, i int) []ExprSet {
+	if isValid(cur) {
+		return append(exprSets, copySet(cur))
 	}
 
 	for j := i; j < len(n); j++ {
 		cur[n[j]] = true
-		defer delete(cur, n[j])
-		return getResilientQuorumsHelper(exprSets, minResilience, n, e, copySet(cur), j+1)
+		exprSets = getQuorumsWhereHelper(exprSets, n, isValid, cur, j+1)
+		delete(cur, n[j])
 	}
+
 	return exprSets
 }
 
-// readQuorumLatency return the latency of a read quorum.
-func (qs QuorumSystem) readQuorumLatency(quorum []Node) (uint, error) {
-	return qs.quorumLatency(quorum, qs.IsReadQuorum)
+// isResilientQuorum returns true if the set is still a quorum of e after removing any f of its nodes.
+func isResilientQuorum(f uint, set ExprSet, e Quorum) bool {
+	if f == 0 || len(set) == 0 {
+		return e.IsQuorum(set)
+	}
+
+	for _, failure := range combinations(setToArr(set), f) {
+		if !e.IsQuorum(remove(set, failure...)) {
+			return false
+		}
+	}
+
+	return true
 }
 
-// writeQuorumLatency returns the latency of a write quorum.
-func (qs QuorumSystem) writeQuorumLatency(quorum []Node) (uint, error) {
-	return qs.quorumLatency(quorum, qs.IsWriteQuorum)
+// readQuorumLatency return the latency of a read quorum, given the latency of each node.
+func (qs QuorumSystem) readQuorumLatency(quorum []Node, latency func(n Node) time.Duration) (time.Duration, error) {
+	return qs.quorumLatency(quorum, qs.IsReadQuorum, latency)
 }
 
-// quorumLatency returns the minimum latency of a given quorum.
-func (qs QuorumSystem) quorumLatency(quorum []Node, isQuorum func(set ExprSet) bool) (uint, error) {
+// writeQuorumLatency returns the latency of a write quorum, given the latency of each node.
+func (qs QuorumSystem) writeQuorumLatency(quorum []Node, latency func(n Node) time.Duration) (time.Duration, error) {
+	return qs.quorumLatency(quorum, qs.IsWriteQuorum, latency)
+}
+
+// quorumLatency returns the minimum latency of a given quorum, given the latency of each node.
+func (qs QuorumSystem) quorumLatency(quorum []Node, isQuorum func(set ExprSet) bool,
+	latency func(n Node) time.Duration) (time.Duration, error) {
 	sortedQ := make([]Node, 0)
 
 	for _, q := range quorum {
@@ -511,7 +806,7 @@ func (qs QuorumSystem) quorumLatency(quorum []Node, isQuorum func(set ExprSet) b
 	}
 
 	nodeLatency := func(p1, p2 *Node) bool {
-		return *p1.Latency < *p2.Latency
+		return latency(*p1) < latency(*p2)
 	}
 
 	By(nodeLatency).Sort(sortedQ)
@@ -524,7 +819,7 @@ func (qs QuorumSystem) quorumLatency(quorum []Node, isQuorum func(set ExprSet) b
 		}
 
 		if isQuorum(xNodes) {
-			return *sortedQ[i].Latency, nil
+			return latency(sortedQ[i]), nil
 		}
 	}
 
@@ -532,6 +827,35 @@ func (qs QuorumSystem) quorumLatency(quorum []Node, isQuorum func(set ExprSet) b
 
 }
 
+// loadOptimalProblem describes the linear program solved by loadOptimalStrategy, and where its variables and
+// constraints are.
+type loadOptimalProblem struct {
+	def             lpDefinition
+	readQuorumVars  []lpVariable
+	writeQuorumVars []lpVariable
+	// clientReadQuorumVars and clientWriteQuorumVars are the quorum variables of each client of the problem, those
+	// of the first client are readQuorumVars and writeQuorumVars.
+	clientReadQuorumVars  [][]lpVariable
+	clientWriteQuorumVars [][]lpVariable
+	// fractions are the sorted read fractions of the workload, with their probabilities.
+	fractions     []Fraction
+	probabilities []Probability
+	// loadRows maps, for each fraction, a node to the index of its load constraint.
+	loadRows []map[Node]int
+}
+
+// lpSolution describes the optimal solution of a lpDefinition.
+type lpSolution struct {
+	// Primal is the value of each variable.
+	Primal []float64
+	// RowDuals is the dual value of each constraint, i.e. the change of the objective per unit of change of its bound.
+	RowDuals []float64
+	// ReducedCosts is the reduced cost of each variable.
+	ReducedCosts []float64
+	// Objective is the optimal value of the objective.
+	Objective float64
+}
+
 func (qs QuorumSystem) loadOptimalStrategy(
 	optimize OptimizeType,
 	readQuorums []ExprSet,
@@ -539,195 +863,410 @@ func (qs QuorumSystem) loadOptimalStrategy(
 	readFraction DistributionValues,
 	loadLimit *float64,
 	networkLimit *float64,
-	latencyLimit *float64) (*Strategy, error) {
+	latencyLimit *float64,
+	costLimit *float64,
+	weights *ObjectiveWeights) (*Strategy, error) {
+
+	problem, err := qs.buildLoadOptimalProblem(optimize, readQuorums, writeQuorums, readFraction,
+		loadLimit, networkLimit, latencyLimit, costLimit, weights)
+
+	if err != nil {
+		return nil, err
+	}
+
+	solution, err := problem.def.solve()
+
+	if err != nil {
+		return nil, err
+	}
+
+	newStrategy := problem.strategy(qs, solution)
+
+	return &newStrategy, nil
+}
+
+// buildLoadOptimalProblem returns the linear program finding the read and write quorum probabilities that
+// optimize the given objective under the given limits.
+//
+// The variables are the probabilities of the read quorums, the probabilities of the write quorums and, when the
+// load is optimized or limited, the load l_fr of each read fraction fr:
+//
+//	Vars: r_0, ..., r_n, w_0, ..., w_m, l_fr_0, ..., l_fr_k
+//  Constraints:
+//		0 ≤ r_i, w_j ≤ 1
+//		r_0 + ... + r_n = 1
+//		w_0 + ... + w_m = 1
+//		load_x(fr) - l_fr ≤ 0 for each node x and read fraction fr
+//  Obj:
+//		Σ p(fr) * l_fr | network_def | latency_def | cost_def | weighted sum of the four
+func (qs QuorumSystem) buildLoadOptimalProblem(
+	optimize OptimizeType,
+	readQuorums []ExprSet,
+	writeQuorums []ExprSet,
+	readFraction DistributionValues,
+	loadLimit *float64,
+	networkLimit *float64,
+	latencyLimit *float64,
+	costLimit *float64,
+	weights *ObjectiveWeights) (loadOptimalProblem, error) {
+
+	return qs.buildClientsLoadOptimalProblem(optimize, readQuorums, writeQuorums, readFraction, loadLimit,
+		networkLimit, latencyLimit, costLimit, weights, []lpClient{{share: 1, latency: Node.GetLatency}})
+}
+
+// lpClient describes the clients of a linear program: the share of the operations they issue, and their latency to
+// each node.
+type lpClient struct {
+	share   float64
+	latency func(Node) time.Duration
+}
+
+// buildClientsLoadOptimalProblem returns the linear program of buildLoadOptimalProblem for several clients, each with
+// its own read and write quorum probabilities. The load of a node sums the operations of every client, and the
+// network load, the latency and the cost are averaged over the clients, weighted by their shares.
+//
+// The variables of the first client come first, so that the load variables are where buildLoadOptimalProblem puts
+// them, followed by the variables of the other clients:
+//
+//	Vars: r_0^0, ..., w_m^0, l_fr_0, ..., l_fr_k, r_0^1, ..., w_m^1, ...
+func (qs QuorumSystem) buildClientsLoadOptimalProblem(
+	optimize OptimizeType,
+	readQuorums []ExprSet,
+	writeQuorums []ExprSet,
+	readFraction DistributionValues,
+	loadLimit *float64,
+	networkLimit *float64,
+	latencyLimit *float64,
+	costLimit *float64,
+	weights *ObjectiveWeights,
+	clients []lpClient) (loadOptimalProblem, error) {
 
 	ninf := math.Inf(-1)
 	pinf := math.Inf(1)
 
-	readQuorumVars, xToReadQuorumVars := getOptimizationVars(readQuorums, "r%d", 0)
-	writeQuorumVars, xToWriteQuorumVars := getOptimizationVars(writeQuorums, "w%d", len(readQuorums))
+	fractions := make([]Fraction, 0)
 
-	fr := 0.0
-
-	for k, v := range readFraction {
-		fr += k * v
+	for k := range readFraction {
+		fractions = append(fractions, k)
 	}
 
-	buildNetworkDef := func(networkLimit *float64) lpDefinition {
-		def := newDefinitionWithVarsAndConstraints(readQuorumVars, writeQuorumVars)
+	sort.Float64s(fractions)
 
-		objExpr := make([]float64, len(def.Vars))
+	probabilities := make([]Probability, 0)
 
-		// network_def  - inf <= network_def <= +inf
-		for _, v := range readQuorumVars {
-			objExpr[v.Index] = fr * float64(len(v.Quorum))
+	for _, k := range fractions {
+		probabilities = append(probabilities, readFraction[k])
+	}
+
+	quorumVarsPerClient := len(readQuorums) + len(writeQuorums)
+	loadVars := make([]lpVariable, 0)
+
+	if optimize == Load || loadLimit != nil || (optimize == Weighted && weights.Load > 0) {
+		for i := range fractions {
+			loadVars = append(loadVars, lpVariable{Name: fmt.Sprintf("l%d", i), UBound: pinf, LBound: 0,
+				Value: 1.0, Index: quorumVarsPerClient + i})
 		}
+	}
+
+	readQuorumVars := make([][]lpVariable, 0, len(clients))
+	writeQuorumVars := make([][]lpVariable, 0, len(clients))
+	xToReadQuorumVars := make([]map[Expr][]lpVariable, 0, len(clients))
+	xToWriteQuorumVars := make([]map[Expr][]lpVariable, 0, len(clients))
+	allVars := make([][]lpVariable, 0)
+
+	for c := range clients {
+		offset := 0
 
-		for _, v := range writeQuorumVars {
-			objExpr[v.Index] = (1 - fr) * float64(len(v.Quorum))
+		if c > 0 {
+			offset = c*quorumVarsPerClient + len(loadVars)
 		}
 
-		objExpr = append([]float64{ninf}, objExpr...)
+		r, xr := getOptimizationVars(readQuorums, "r%d", offset)
+		w, xw := getOptimizationVars(writeQuorums, "w%d", offset+len(readQuorums))
 
-		if networkLimit == nil {
-			objExpr = append(objExpr, pinf)
-		} else {
-			objExpr = append(objExpr, *networkLimit)
+		readQuorumVars = append(readQuorumVars, r)
+		writeQuorumVars = append(writeQuorumVars, w)
+		xToReadQuorumVars = append(xToReadQuorumVars, xr)
+		xToWriteQuorumVars = append(xToWriteQuorumVars, xw)
+		allVars = append(allVars, r, w)
+
+		if c == 0 {
+			allVars = append(allVars, loadVars)
 		}
+	}
 
-		def.Objectives = append(def.Objectives, objExpr)
+	def := newDefinitionWithVarsAndConstraints(allVars...)
+	nVars := len(def.Vars)
 
-		return def
+	// newRow returns a constraint lower ≤ Σ coefficients ≤ upper with all the coefficients set to 0.
+	newRow := func(lower float64, upper float64) []float64 {
+		row := make([]float64, nVars+2)
+		row[0] = lower
+		row[nVars+1] = upper
+		return row
 	}
 
-	buildLatencyDef := func(latencyLimit *float64) (lpDefinition, error) {
-		def := newDefinitionWithVarsAndConstraints(readQuorumVars, writeQuorumVars)
+	fr := 0.0
 
-		// building latency objs | -inf <= latency_def <= inf
-		obj := make([]float64, len(def.Vars))
+	for k, v := range readFraction {
+		fr += k * v
+	}
 
-		for _, v := range readQuorumVars {
-			nodes := make([]Node, 0)
+	nodes := qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
 
-			for x := range v.Quorum {
-				n := qs.GetNodeByName(x.String())
-				nodes = append(nodes, n)
-			}
+	buildNetworkDef := func(networkLimit *float64) []float64 {
+		// network_def  - inf <= network_def <= +inf
+		row := newRow(ninf, pinf)
 
-			l, err := qs.readQuorumLatency(nodes)
+		if networkLimit != nil {
+			row[nVars+1] = *networkLimit
+		}
 
-			if err != nil {
-				return lpDefinition{}, fmt.Errorf("error on readQuorumLatency %s", err)
+		for c, client := range clients {
+			for _, v := range readQuorumVars[c] {
+				row[1+v.Index] = client.share * fr * qs.readQuorumNetworkLoad(v.Quorum)
 			}
 
-			obj[v.Index] = fr * v.Value * float64(l)
+			for _, v := range writeQuorumVars[c] {
+				row[1+v.Index] = client.share * (1 - fr) * qs.writeQuorumNetworkLoad(v.Quorum)
+			}
 		}
 
-		for _, v := range writeQuorumVars {
-			nodes := make([]Node, 0)
+		return row
+	}
 
-			for x := range v.Quorum {
-				n := qs.GetNodeByName(x.String())
-				nodes = append(nodes, n)
-			}
+	buildCostDef := func(costLimit *float64) []float64 {
+		// cost_def  - inf <= cost_def <= +inf
+		row := newRow(ninf, pinf)
 
-			l, err := qs.writeQuorumLatency(nodes)
+		if costLimit != nil {
+			row[nVars+1] = *costLimit
+		}
 
-			if err != nil {
-				return lpDefinition{}, fmt.Errorf("error on writeQuorumLatency %s", err)
+		for c, client := range clients {
+			for _, v := range readQuorumVars[c] {
+				row[1+v.Index] = client.share * fr * qs.readQuorumCost(v.Quorum)
 			}
 
-			obj[v.Index] = (1 - fr) * v.Value * float64(l)
+			for _, v := range writeQuorumVars[c] {
+				row[1+v.Index] = client.share * (1 - fr) * qs.writeQuorumCost(v.Quorum)
+			}
 		}
 
-		obj = append([]float64{ninf}, obj...)
+		return row
+	}
+
+	buildLatencyDef := func(latencyLimit *float64) ([]float64, error) {
+		// building latency objs | -inf <= latency_def <= inf
+		row := newRow(ninf, pinf)
 
-		if latencyLimit == nil {
-			obj = append(obj, pinf)
-		} else {
-			obj = append(obj, *latencyLimit)
+		if latencyLimit != nil {
+			row[nVars+1] = *latencyLimit
 		}
 
-		def.Objectives = append(def.Objectives, obj)
+		for c, client := range clients {
+			for _, v := range readQuorumVars[c] {
+				l, err := qs.readQuorumLatency(quorumNodes(qs, v.Quorum), client.latency)
 
-		return def, nil
-	}
+				if err != nil {
+					return nil, fmt.Errorf("error on readQuorumLatency %s", err)
+				}
 
-	buildLoadDef := func(loadLimit *float64, fr float64) (lpDefinition, error) {
-		def := newDefinitionWithVarsAndConstraints(readQuorumVars, writeQuorumVars)
-		// l def
-		def.Vars = append(def.Vars, 1.0)
-		def.Constraints = append(def.Constraints, [2]float64{ninf, pinf})
+				row[1+v.Index] = client.share * fr * v.Value * milliseconds(l)
+			}
 
-		// Load formula
-		for n := range qs.GetNodes() {
-			tmp := make([]float64, len(def.Vars))
+			for _, v := range writeQuorumVars[c] {
+				l, err := qs.writeQuorumLatency(quorumNodes(qs, v.Quorum), client.latency)
 
-			if _, ok := xToReadQuorumVars[n]; ok {
-				vs := xToReadQuorumVars[n]
-				for _, v := range vs {
-					tmp[v.Index] += fr * v.Value / float64(*qs.GetNodeByName(n.Name).ReadCapacity)
+				if err != nil {
+					return nil, fmt.Errorf("error on writeQuorumLatency %s", err)
 				}
+
+				row[1+v.Index] = client.share * (1 - fr) * v.Value * milliseconds(l)
 			}
+		}
+
+		return row, nil
+	}
 
-			if _, ok := xToWriteQuorumVars[n]; ok {
-				vs := xToWriteQuorumVars[n]
-				for _, v := range vs {
-					tmp[v.Index] += (1 - fr) * v.Value / float64(*qs.GetNodeByName(n.Name).WriteCapacity)
+	// buildLoadDef returns, for each node x, the constraint load_x(fr) - l_fr ≤ 0.
+	buildLoadDef := func(fr float64, loadVar lpVariable) map[Node][]float64 {
+		rows := make(map[Node][]float64)
+
+		for _, n := range nodes {
+			row := newRow(ninf, 0)
+
+			for c, client := range clients {
+				for _, v := range xToReadQuorumVars[c][n] {
+					row[1+v.Index] += client.share * fr * v.Value / qs.GetNodeByName(n.Name).GetReadCapacity()
+				}
+
+				for _, v := range xToWriteQuorumVars[c][n] {
+					row[1+v.Index] += client.share * (1 - fr) * v.Value / qs.GetNodeByName(n.Name).GetWriteCapacity()
 				}
 			}
 
-			def.Objectives = append(def.Objectives, tmp)
+			row[1+loadVar.Index] = -1
+			rows[n] = row
 		}
-		return def, nil
+
+		return rows
 	}
 
-	// Declare a new  Simplex problem with a clp.Minimize optimization direction.
-	simp := clp.NewSimplex()
-	simp.SetOptimizationDirection(clp.Minimize)
+	objective := make([]float64, nVars)
+	loadRows := make([]map[Node]int, 0)
 
-	def := lpDefinition{}
+	for i, v := range loadVars {
+		rows := buildLoadDef(fractions[i], v)
+		indexes := make(map[Node]int)
+
+		for _, n := range nodes {
+			indexes[n] = len(def.Objectives)
+			def.Objectives = append(def.Objectives, rows[n])
+		}
+
+		loadRows = append(loadRows, indexes)
+	}
 
 	if optimize == Load {
-		def = getLoadObjective(readFraction, loadLimit, buildLoadDef)
+		for i, v := range loadVars {
+			objective[v.Index] = probabilities[i]
+		}
 	} else if optimize == Network {
-		def = buildNetworkDef(nil)
+		copy(objective, buildNetworkDef(nil)[1:nVars+1])
 	} else if optimize == Latency {
-		def, _ = buildLatencyDef(nil)
+		row, err := buildLatencyDef(nil)
+
+		if err != nil {
+			return loadOptimalProblem{}, err
+		}
+
+		copy(objective, row[1:nVars+1])
+	} else if optimize == Cost {
+		copy(objective, buildCostDef(nil)[1:nVars+1])
+	} else if optimize == Weighted {
+		if weights.Load > 0 {
+			for i, v := range loadVars {
+				objective[v.Index] += weights.Load * probabilities[i]
+			}
+		}
+
+		if weights.Network > 0 {
+			for i, c := range buildNetworkDef(nil)[1 : nVars+1] {
+				objective[i] += weights.Network * c
+			}
+		}
+
+		if weights.Latency > 0 {
+			row, err := buildLatencyDef(nil)
+
+			if err != nil {
+				return loadOptimalProblem{}, err
+			}
+
+			for i, c := range row[1 : nVars+1] {
+				objective[i] += weights.Latency * c
+			}
+		}
+
+		if weights.Cost > 0 {
+			for i, c := range buildCostDef(nil)[1 : nVars+1] {
+				objective[i] += weights.Cost * c
+			}
+		}
 	}
 
-	// The sum of the read and write quorums probabilities must be 1.
-	sumOfReadProbabilities, sumOfWriteProbabilities := getTotalProbabilityObjectives(optimize, readQuorumVars, writeQuorumVars)
-	def.Objectives = append(def.Objectives, sumOfReadProbabilities)
-	def.Objectives = append(def.Objectives, sumOfWriteProbabilities)
+	def.Vars = objective
+
+	// The sum of the read and write quorums probabilities of each client must be 1.
+	for c := range clients {
+		sumOfReadProbabilities, sumOfWriteProbabilities := newRow(1, 1), newRow(1, 1)
+
+		for _, v := range readQuorumVars[c] {
+			sumOfReadProbabilities[1+v.Index] = 1
+		}
+
+		for _, v := range writeQuorumVars[c] {
+			sumOfWriteProbabilities[1+v.Index] = 1
+		}
+
+		def.Objectives = append(def.Objectives, sumOfReadProbabilities, sumOfWriteProbabilities)
+	}
 
 	if loadLimit != nil {
-		defTemp := getLoadObjective(readFraction, loadLimit, buildLoadDef)
-		def.Vars = append(def.Vars, 0)
+		row := newRow(ninf, *loadLimit)
 
-		for r := 0; r < len(def.Objectives); r++ {
-			if len(def.Objectives[r]) != len(def.Vars) {
-				def.Objectives[r] = insertAt(def.Objectives[r], len(def.Objectives[r])-1, 0.0)
-			}
+		for i, v := range loadVars {
+			row[1+v.Index] = probabilities[i]
 		}
 
-		b := [2]float64{ninf, pinf}
-		def.Constraints = append(def.Constraints, b)
-		def.Objectives = append(def.Objectives, defTemp.Objectives...)
+		def.Objectives = append(def.Objectives, row)
 	}
 
 	if networkLimit != nil {
-		def.Objectives = merge(def.Objectives, buildNetworkDef(networkLimit).Objectives)
+		def.Objectives = append(def.Objectives, buildNetworkDef(networkLimit))
 	}
 
 	if latencyLimit != nil {
-		defTemp, _ := buildLatencyDef(latencyLimit)
-		def.Objectives = merge(def.Objectives, defTemp.Objectives)
-	}
+		row, err := buildLatencyDef(latencyLimit)
 
-	simp.EasyLoadDenseProblem(def.Vars, def.Constraints, def.Objectives)
-	// Solve the optimization problem.
-	status := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
-	soln := simp.PrimalColumnSolution()
+		if err != nil {
+			return loadOptimalProblem{}, err
+		}
 
-	if status != clp.Optimal {
-		return nil, fmt.Errorf("no optimal strategy found")
+		def.Objectives = append(def.Objectives, row)
 	}
 
+	if costLimit != nil {
+		def.Objectives = append(def.Objectives, buildCostDef(costLimit))
+	}
+
+	return loadOptimalProblem{
+		def:                   def,
+		readQuorumVars:        readQuorumVars[0],
+		writeQuorumVars:       writeQuorumVars[0],
+		clientReadQuorumVars:  readQuorumVars,
+		clientWriteQuorumVars: writeQuorumVars,
+		fractions:             fractions,
+		probabilities:         probabilities,
+		loadRows:              loadRows,
+	}, nil
+}
+
+// strategy returns the Strategy defined by the solution of the problem.
+func (p loadOptimalProblem) strategy(qs QuorumSystem, solution lpSolution) Strategy {
 	readSigma := make([]SigmaRecord, 0)
 	writeSigma := make([]SigmaRecord, 0)
 
-	for _, v := range readQuorumVars {
-		readSigma = append(readSigma, SigmaRecord{Quorum: v.Quorum, Probability: soln[v.Index]})
+	for _, v := range p.readQuorumVars {
+		readSigma = append(readSigma, SigmaRecord{Quorum: v.Quorum, Probability: solution.Primal[v.Index]})
 	}
 
-	for _, v := range writeQuorumVars {
-		writeSigma = append(writeSigma, SigmaRecord{Quorum: v.Quorum, Probability: soln[v.Index]})
+	for _, v := range p.writeQuorumVars {
+		writeSigma = append(writeSigma, SigmaRecord{Quorum: v.Quorum, Probability: solution.Primal[v.Index]})
 	}
 
-	newStrategy := NewStrategy(qs, Sigma{Values: readSigma}, Sigma{Values: writeSigma})
+	return NewStrategy(qs, Sigma{Values: readSigma}, Sigma{Values: writeSigma})
+}
+
+// errNoOptimalStrategy is the error of an infeasible or unbounded linear program, e.g. when the limits cannot be met.
+var errNoOptimalStrategy = errors.New("no optimal strategy found")
 
-	return &newStrategy, nil
+// solve minimizes the lpDefinition with the Solver set by SetSolver and returns its optimal solution. The infeasible
+// and unbounded programs are reported with errNoOptimalStrategy, the other errors of the Solver are returned as is.
+func (def lpDefinition) solve() (lpSolution, error) {
+	solution, err := solver.Solve(LinearProgram{Objective: def.Vars, Bounds: def.Constraints, Rows: def.Objectives})
+
+	if errors.Is(err, ErrInfeasible) || errors.Is(err, ErrUnbounded) {
+		return lpSolution{}, fmt.Errorf("%w: %v", errNoOptimalStrategy, err)
+	}
+
+	if err != nil {
+		return lpSolution{}, err
+	}
+
+	return lpSolution(solution), nil
 }
 
 // getOptimizationVars returns the list lpVariable for a list of quorums.
@@ -753,103 +1292,100 @@ func getOptimizationVars(quorums []ExprSet, name string, startIndex int) (quorum
 	return quorumVars, quorumToQuorumVar
 }
 
-// getTotalProbabilityObjectives returns two objectives targeting the read and write quorums: The sum of the probabilities must be 1.
-func getTotalProbabilityObjectives(optimize OptimizeType, readQuorumVars []lpVariable, writeQuorumVars []lpVariable) (readQConstraint []float64, writeQConstraint []float64) {
-	// read quorum constraint
-	readQConstraint = make([]float64, 0)
-	readQConstraint = append(readQConstraint, 1)
+func remove(set ExprSet, g ...Expr) ExprSet {
+	newSet := copySet(set)
 
-	for range readQuorumVars {
-		readQConstraint = append(readQConstraint, 1.0)
+	for _, e := range g {
+		delete(newSet, e)
 	}
 
-	for range writeQuorumVars {
-		readQConstraint = append(readQConstraint, 0.0)
+	return newSet
+}
+
+func intersects(lhs ExprSet, rhs ExprSet) bool {
+	for k := range lhs {
+		if rhs[k] {
+			return true
+		}
 	}
+	return false
+}
 
-	if optimize == Load {
-		readQConstraint = append(readQConstraint, 0.0)
+// setKey returns a canonical string for an ExprSet, made of the sorted names of its elements.
+func setKey(set ExprSet) string {
+	names := make([]string, 0, len(set))
+
+	for k := range set {
+		names = append(names, k.String())
 	}
 
-	readQConstraint = append(readQConstraint, 1)
+	sort.Strings(names)
 
-	// write quorum constraint
-	writeQConstraint = make([]float64, 0)
-	writeQConstraint = append(writeQConstraint, 1)
+	return strings.Join(names, ",")
+}
 
-	for range readQuorumVars {
-		writeQConstraint = append(writeQConstraint, 0.0)
-	}
+// isDomainResilientQuorum returns true if the set spans at least minDomains failure domains and is still a quorum
+// of e after removing the nodes of any d of its failure domains.
+func isDomainResilientQuorum(d uint, minDomains uint, nodeToDomain map[Node]string, set ExprSet, e Quorum) bool {
+	domainToNodes := make(map[string][]Expr)
 
-	for range writeQuorumVars {
-		writeQConstraint = append(writeQConstraint, 1.0)
+	for x := range set {
+		domain := nodeToDomain[x.(Node)]
+		domainToNodes[domain] = append(domainToNodes[domain], x)
 	}
 
-	if optimize == Load {
-		writeQConstraint = append(writeQConstraint, 0.0)
+	if uint(len(domainToNodes)) < minDomains {
+		return false
 	}
 
-	writeQConstraint = append(writeQConstraint, 1)
-	return readQConstraint, writeQConstraint
-}
-
-// getLoadObjective returns the lpDefinition for the load objective.
-func getLoadObjective(readFraction DistributionValues, loadLimit *float64,
-	frLoad func(loadLimit *float64, fr float64) (lpDefinition, error)) lpDefinition {
+	domains := make([]string, 0, len(domainToNodes))
 
-	def := lpDefinition{}
-	ninf := math.Inf(-1)
-
-	for fr, p := range readFraction {
-		def, _ = frLoad(nil, fr)
+	for domain := range domainToNodes {
+		domains = append(domains, domain)
+	}
 
-		for r := 0; r < len(def.Objectives); r++ {
+	if d > uint(len(domains)) {
+		d = uint(len(domains))
+	}
 
-			for c := 0; c < len(def.Objectives[r]); c++ {
-				def.Objectives[r][c] = def.Objectives[r][c] * p
-			}
-			loadLimitValue := 1.0
+	for _, failure := range indexCombinations(len(domains), int(d)) {
+		failed := make([]Expr, 0)
 
-			if loadLimit != nil {
-				loadLimitValue = *loadLimit
-			}
+		for _, i := range failure {
+			failed = append(failed, domainToNodes[domains[i]]...)
+		}
 
-			def.Objectives[r] = append([]float64{ninf}, def.Objectives[r]...)
-			def.Objectives[r][len(def.Objectives[r])-1] = -loadLimitValue
-			def.Objectives[r] = append(def.Objectives[r], 0)
+		if !e.IsQuorum(remove(set, failed...)) {
+			return false
 		}
 	}
 
-	return def
+	return true
 }
 
-func insertAt(a []float64, index int, value float64) []float64 {
-	if len(a) == index { // nil or empty slice or after last element
-		return append(a, value)
-	}
-	a = append(a[:index+1], a[index:]...) // index < len(a)
-	a[index] = value
-	return a
-}
+// indexCombinations returns all the combinations of k indices out of [0, n).
+func indexCombinations(n int, k int) [][]int {
+	result := make([][]int, 0)
+	cur := make([]int, 0, k)
 
-func merge(obj [][]float64, lobj [][]float64) [][]float64 {
-
-	if len(obj[0]) != len(lobj[0]) {
-		lobj[0] = insertAt(lobj[0], len(lobj[0])-1, 0)
-	}
-	obj = append(obj, lobj[0])
+	var helper func(i int)
 
-	return obj
-}
-
-func remove(set ExprSet, g ...Expr) ExprSet {
-	newSet := copySet(set)
+	helper = func(i int) {
+		if len(cur) == k {
+			result = append(result, append([]int{}, cur...))
+			return
+		}
 
-	for _, e := range g {
-		delete(newSet, e)
+		for j := i; j < n; j++ {
+			cur = append(cur, j)
+			helper(j + 1)
+			cur = cur[:len(cur)-1]
+		}
 	}
 
-	return newSet
+	helper(0)
+
+	return result
 }
 
 func copySet(set ExprSet) ExprSet {
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestInit(t *testing.T) {
@@ -280,8 +281,8 @@ func TestOptimalStrategyLoad(t *testing.T) {
 	const float64EqualityThreshold = 1e-9
 
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1), NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3), NewNodeWithCapacityAndLatency("d", 2, 1, 4)
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond), NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
 
 	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
 
@@ -410,8 +411,8 @@ func TestOptimalStrategyNetwork(t *testing.T) {
 	const float64EqualityThreshold = 1e-9
 
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1), NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3), NewNodeWithCapacityAndLatency("d", 2, 1, 4)
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond), NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
 
 	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
 
@@ -498,8 +499,8 @@ func TestOptimalStrategyLatency(t *testing.T) {
 	const float64EqualityThreshold = 1e-9
 
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1), NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3), NewNodeWithCapacityAndLatency("d", 2, 1, 4)
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond), NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
 
 	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
 
@@ -589,10 +590,100 @@ func TestOptimalStrategyLatency(t *testing.T) {
 	assert.Assert(t, math.Abs(latency-3) <= float64EqualityThreshold, fmt.Sprintf("Actual:%f", latency))
 }
 
+func TestOptimalStrategyWeighted(t *testing.T) {
+
+	const float64EqualityThreshold = 1e-9
+
+	a, b := NewNodeWithCapacityAndLatency("a", 1, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 1, 1, 2*time.Millisecond)
+
+	// Reading from a with probability p gives a load of max(p, 1 - p) and a latency of 2 - p. The optimal load is 0.5
+	// and the optimal latency is 1, so the normalized objective is α * 2 * max(p, 1 - p) + γ * (2 - p).
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	tests := []struct {
+		weights         ObjectiveWeights
+		expectedLoad    float64
+		expectedLatency float64
+	}{
+		{ObjectiveWeights{Load: 1}, 0.5, 1.5},
+		{ObjectiveWeights{Latency: 1}, 1, 1},
+		{ObjectiveWeights{Load: 1, Latency: 1}, 0.5, 1.5},
+		{ObjectiveWeights{Load: 1, Latency: 3}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		strategyOptions := StrategyOptions{
+			Optimize: Weighted,
+			Weights:  tt.weights,
+			ReadFraction: QuorumDistribution{
+				values: map[Fraction]Weight{1: 1}},
+		}
+
+		load, err := qs.Load(strategyOptions)
+		assert.NilError(t, err)
+		assert.Assert(t, math.Abs(load-tt.expectedLoad) <= float64EqualityThreshold, fmt.Sprintf("Actual:%f", load))
+
+		latency, err := qs.Latency(strategyOptions)
+		assert.NilError(t, err)
+		assert.Assert(t, math.Abs(latency-tt.expectedLatency) <= float64EqualityThreshold, fmt.Sprintf("Actual:%f", latency))
+	}
+
+	_, err := qs.Strategy(initializeStrategyOptions(StrategyOptions{
+		Optimize: Weighted,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}))
+	assert.Error(t, err, "at least one weight must be > 0 when optimizing for weighted")
+
+	_, err = qs.Strategy(initializeStrategyOptions(StrategyOptions{
+		Optimize: Weighted,
+		Weights:  ObjectiveWeights{Load: 1, Network: -1},
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}))
+	assert.Error(t, err, "weights must be >= 0")
+}
+
+func TestOptimalStrategyRealValuedNodes(t *testing.T) {
+
+	const float64EqualityThreshold = 1e-9
+
+	// Nodes without capacities nor latency, the latency in milliseconds defaults to 1 second.
+	a, b := Node{Name: "a"}, Node{Name: "b"}
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	strategyOptions := StrategyOptions{
+		Optimize: Latency,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{1: 1}},
+	}
+
+	latency, err := qs.Latency(strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(latency-1000) <= float64EqualityThreshold, fmt.Sprintf("Actual:%f", latency))
+
+	// Reads are spread proportionally to the fractional read capacities.
+	c, d := NewNodeWithCapacityAndLatency("c", 1.5, 1, 500*time.Microsecond), NewNodeWithCapacity("d", 0.5, 1)
+	qs = NewQuorumSystemWithReads(c.Add(d))
+
+	strategyOptions.Optimize = Load
+	load, err := qs.Load(strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-0.5) <= float64EqualityThreshold, fmt.Sprintf("Actual:%f", load))
+
+	latency, err = qs.Latency(strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(latency-(0.75*0.5+0.25*1000)) <= float64EqualityThreshold, fmt.Sprintf("Actual:%f", latency))
+
+	zero := NewNodeWithCapacity("e", 0, 1)
+	_, err = NewQuorumSystemWithReads(zero).Load(strategyOptions)
+	assert.Error(t, err, "the capacities of node e must be > 0")
+}
+
 func TestOptimalStrategyIllegalSpecs(t *testing.T) {
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1), NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3), NewNodeWithCapacityAndLatency("d", 2, 1, 4)
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond), NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
 
 	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
 
@@ -636,8 +727,8 @@ func TestOptimalStrategyIllegalSpecs(t *testing.T) {
 
 func TestOptimalStrategyUnsatisfiableConstraints(t *testing.T) {
 	a, b, c, d :=
-		NewNodeWithCapacityAndLatency("a", 2, 1, 1), NewNodeWithCapacityAndLatency("b", 2, 1, 2),
-		NewNodeWithCapacityAndLatency("c", 2, 1, 3), NewNodeWithCapacityAndLatency("d", 2, 1, 4)
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond), NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
 
 	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
 
@@ -651,7 +742,7 @@ func TestOptimalStrategyUnsatisfiableConstraints(t *testing.T) {
 	}
 
 	_, err := qs.Load(strategyOptions)
-	assert.Assert(t, err.Error() == "no optimal strategy found")
+	assert.Error(t, err, "no optimal strategy found: the linear program is infeasible")
 
 	latencyLimit := 2.0
 
@@ -663,7 +754,7 @@ func TestOptimalStrategyUnsatisfiableConstraints(t *testing.T) {
 	}
 
 	_, err = qs.Load(strategyOptions)
-	assert.Assert(t, err.Error() == "no optimal strategy found")
+	assert.Error(t, err, "no optimal strategy found: the linear program is infeasible")
 
 	latencyLimit = 2.0
 	loadLimit := 0.25
@@ -677,5 +768,115 @@ func TestOptimalStrategyUnsatisfiableConstraints(t *testing.T) {
 	}
 
 	//_, err := qs.Load(strategyOptions)
-	//assert.Assert(t, err.Error() == "no optimal strategy found")
+	//assert.Error(t, err, "no optimal strategy found: the linear program is infeasible")
+}
+
+func TestMinimalFailureSets(t *testing.T) {
+	assertSets := func(actual []ExprSet, expected []string) {
+		actualString := make([]string, 0)
+
+		for _, set := range actual {
+			actualString = append(actualString, setKey(set))
+		}
+
+		assert.DeepEqual(t, actualString, expected)
+	}
+
+	a, b, c, d := NewNode("a"), NewNode("b"), NewNode("c"), NewNode("d")
+
+	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
+
+	assertSets(qs.MinimalReadFailureSets(0), []string{"a,c", "a,d", "b,c", "b,d"})
+	assertSets(qs.MinimalWriteFailureSets(0), []string{"a,b", "c,d"})
+	assertSets(qs.MinimalReadFailureSets(1), []string{})
+	assertSets(qs.MinimalWriteFailureSets(2), []string{"a,b", "c,d"})
+
+	qs = NewQuorumSystemWithReads(a.Add(b).Add(c))
+
+	assertSets(qs.MinimalReadFailureSets(0), []string{"a,b,c"})
+	assertSets(qs.MinimalWriteFailureSets(0), []string{"a", "b", "c"})
+
+	smallest, err := qs.SmallestReadFailureSet()
+	assert.NilError(t, err)
+	assert.Equal(t, setKey(smallest), "a,b,c")
+
+	smallest, err = qs.SmallestFailureSet()
+	assert.NilError(t, err)
+	assert.Equal(t, uint(len(smallest)), qs.Resilience()+1)
+	assert.Assert(t, !qs.IsWriteQuorum(remove(ExprSet{a: true, b: true, c: true}, setToArr(smallest)...)))
+
+	// Non dup-free quorum system: the failure sets match the exact resilience.
+	qs = NewQuorumSystemWithReads((a.Multiply(b)).Add(b.Multiply(c)).Add(a.Multiply(c)))
+
+	assertSets(qs.MinimalReadFailureSets(0), []string{"a,b", "a,c", "b,c"})
+
+	smallest, err = qs.SmallestReadFailureSet()
+	assert.NilError(t, err)
+	assert.Equal(t, uint(len(smallest)), qs.ReadResilience()+1)
+}
+
+func TestResilientQuorums(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b := NewNodeWithCapacity("a", 3, 1), NewNodeWithCapacity("b", 3, 1)
+	c, d, e := NewNodeWithCapacity("c", 1, 1), NewNodeWithCapacity("d", 1, 1), NewNodeWithCapacity("e", 1, 1)
+	qs := NewQuorumSystemWithReads((a.Add(b)).Multiply(c.Add(d).Add(e)))
+
+	nodes := qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	// Surviving any single failure takes both a and b, and two of c, d and e: every such quorum is found, not only
+	// those of the first branch of the search.
+	keys := make([]string, 0)
+
+	for _, q := range qs.getResilientQuorums(1, nodes, qs.reads) {
+		keys = append(keys, setKey(q))
+	}
+
+	sort.Strings(keys)
+	assert.DeepEqual(t, keys, []string{"a,b,c,d", "a,b,c,e", "a,b,d,e"})
+
+	// Spreading the reads over the three quorums loads each of c, d and e at 2/3, instead of 1 with a single quorum.
+	var rf Distribution = QuorumDistribution{values: DistributionValues{1: 1}}
+	var wf Distribution
+
+	strategy, err := qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, F: 1, ReadFraction: rf}))
+	assert.NilError(t, err)
+
+	load, err := strategy.Load(&rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-2.0/3) <= float64EqualityThreshold, fmt.Sprintf("Actual: %f", load))
+}
+
+func TestOptimalStrategyLoadPerFraction(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b := NewNodeWithCapacity("a", 2, 1), NewNodeWithCapacity("b", 1, 2)
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	var rf Distribution = QuorumDistribution{values: DistributionValues{0.25: 1, 0.75: 1}}
+	var wf Distribution
+
+	// Reading from a with probability x, the max load is 3/4 + x/8 (node a) at fr = 1/4, and the largest of
+	// 1/4 + 3x/8 (node a) and 7/8 - 3x/4 (node b) at fr = 3/4. The average of the two is minimal at x = 5/9, where the
+	// load is 23/36: each read fraction has its own max load in the objective.
+	strategy, err := qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(strategy.nodeToReadProbability[a]-5.0/9) <= float64EqualityThreshold,
+		fmt.Sprintf("Actual: %f", strategy.nodeToReadProbability[a]))
+
+	load, err := strategy.Load(&rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(load-23.0/36) <= float64EqualityThreshold, fmt.Sprintf("Actual: %f", load))
+
+	// The objective of the linear program is the same expected load, Σ p(fr) * l_fr.
+	_, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.NilError(t, err)
+
+	problem, err := qs.buildLoadOptimalProblem(Load, rq, wq, d, nil, nil, nil, nil, nil)
+	assert.NilError(t, err)
+
+	solution, err := problem.def.solve()
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(solution.Objective-23.0/36) <= float64EqualityThreshold)
 }
@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// NodeReport describes the metrics of a node under a strategy.
+type NodeReport struct {
+	Node             Node
+	ReadProbability  Probability
+	WriteProbability Probability
+	Load             float64
+	Utilization      float64
+	Throughput       float64
+}
+
+// StrategyReport describes a strategy: its quorums, the metrics of each node and the aggregate metrics.
+type StrategyReport struct {
+	// ReadQuorums are the read quorums chosen with a non-zero probability, by decreasing probability.
+	ReadQuorums []SigmaRecord
+	// WriteQuorums are the write quorums chosen with a non-zero probability, by decreasing probability.
+	WriteQuorums []SigmaRecord
+	// Nodes are the metrics of each node, sorted by node name.
+	Nodes []NodeReport
+	// Metrics are the aggregate metrics.
+	Metrics Metrics
+}
+
+// Report returns the StrategyReport of the strategy given a read and write Distribution.
+func (s Strategy) Report(rf *Distribution, wf *Distribution) (StrategyReport, error) {
+	metrics, err := s.metrics(rf, wf)
+
+	if err != nil {
+		return StrategyReport{}, err
+	}
+
+	report := StrategyReport{
+		ReadQuorums:  sortedRecords(s.SigmaR),
+		WriteQuorums: sortedRecords(s.SigmaW),
+		Nodes:        make([]NodeReport, 0),
+		Metrics:      metrics,
+	}
+
+	nodes := s.Qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	for _, n := range nodes {
+		load, err := s.NodeLoad(n, rf, wf)
+
+		if err != nil {
+			return StrategyReport{}, err
+		}
+
+		utilization, err := s.NodeUtilization(n, rf, wf)
+
+		if err != nil {
+			return StrategyReport{}, err
+		}
+
+		throughput, err := s.NodeThroughput(n, rf, wf)
+
+		if err != nil {
+			return StrategyReport{}, err
+		}
+
+		report.Nodes = append(report.Nodes, NodeReport{
+			Node:             n,
+			ReadProbability:  s.nodeToReadProbability[n],
+			WriteProbability: s.nodeToWriteProbability[n],
+			Load:             load,
+			Utilization:      *utilization,
+			Throughput:       *throughput,
+		})
+	}
+
+	return report, nil
+}
+
+// String returns the report as text tables.
+func (r StrategyReport) String() string {
+	var sb strings.Builder
+
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Read quorums\tProbability")
+
+	for _, q := range r.ReadQuorums {
+		fmt.Fprintf(tw, "%s\t%.4f\n", formatQuorum(q.Quorum), q.Probability)
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "Write quorums\tProbability")
+
+	for _, q := range r.WriteQuorums {
+		fmt.Fprintf(tw, "%s\t%.4f\n", formatQuorum(q.Quorum), q.Probability)
+	}
+
+	tw.Flush()
+
+	sb.WriteString("\n")
+	tw = tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Node\tRead probability\tWrite probability\tLoad\tUtilization\tThroughput")
+
+	for _, n := range r.Nodes {
+		fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%.4f\t%.4f\t%.4f\n",
+			n.Node.Name, n.ReadProbability, n.WriteProbability, n.Load, n.Utilization, n.Throughput)
+	}
+
+	tw.Flush()
+
+	fmt.Fprintf(&sb, "\nLoad: %.4f | Capacity: %.4f | Network load: %.4f | Latency: %.4f | Resilience: %d\n",
+		r.Metrics.Load, r.Metrics.Capacity, r.Metrics.NetworkLoad, r.Metrics.Latency, r.Metrics.Resilience)
+
+	return sb.String()
+}
+
+// sortedRecords returns the records of sigma with a non-zero probability, by decreasing probability.
+func sortedRecords(sigma Sigma) []SigmaRecord {
+	records := make([]SigmaRecord, 0)
+
+	for _, r := range sigma.Values {
+		if r.Probability > probabilityThreshold {
+			records = append(records, r)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Probability != records[j].Probability {
+			return records[i].Probability > records[j].Probability
+		}
+		return setKey(records[i].Quorum) < setKey(records[j].Quorum)
+	})
+
+	return records
+}
@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	a, b, c, d :=
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond),
+		NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond),
+		NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
+
+	qs := NewQuorumSystemWithReads(a.Multiply(b).Add(c.Multiply(d)))
+
+	sigmaR := Sigma{Values: []SigmaRecord{
+		{Quorum: ExprSet{c: true, d: true}, Probability: 0.25},
+		{Quorum: ExprSet{a: true, b: true}, Probability: 0.75},
+	}}
+	sigmaW := Sigma{Values: []SigmaRecord{
+		{Quorum: ExprSet{a: true, c: true}, Probability: 1},
+		{Quorum: ExprSet{b: true, d: true}, Probability: 0},
+	}}
+
+	strategy := NewStrategy(qs, sigmaR, sigmaW)
+
+	var rf Distribution = QuorumDistribution{values: DistributionValues{1: 1}}
+	var wf Distribution
+
+	report, err := strategy.Report(&rf, &wf)
+	assert.NilError(t, err)
+
+	// Quorums by decreasing probability, without the ones never chosen.
+	assert.Equal(t, len(report.ReadQuorums), 2)
+	assert.Equal(t, report.ReadQuorums[0].Probability, 0.75)
+	assert.Equal(t, len(report.WriteQuorums), 1)
+
+	assert.Equal(t, len(report.Nodes), 4)
+	assert.Equal(t, report.Nodes[0].Node, a)
+	assert.Equal(t, report.Nodes[0].ReadProbability, 0.75)
+	assert.Equal(t, report.Nodes[0].WriteProbability, 1.0)
+	assert.Assert(t, math.Abs(report.Nodes[0].Load-0.375) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(report.Nodes[0].Utilization-1) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(report.Nodes[2].Utilization-1.0/3) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(report.Nodes[2].Throughput-0.25/0.375) < float64EqualityThreshold)
+
+	assert.Assert(t, math.Abs(report.Metrics.Load-0.375) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(report.Metrics.NetworkLoad-2) < float64EqualityThreshold)
+	assert.Assert(t, math.Abs(report.Metrics.Latency-(0.75*2+0.25*4)) < float64EqualityThreshold)
+
+	text := report.String()
+
+	for _, line := range []string{
+		"{a, b}        0.7500",
+		"{a, c}         1.0000",
+		"a     0.7500            1.0000             0.3750  1.0000       2.0000",
+		"Load: 0.3750 | Capacity: 2.6667 | Network load: 2.0000 | Latency: 2.5000 | Resilience: 1",
+	} {
+		assert.Assert(t, strings.Contains(text, line), text)
+	}
+
+	// A node without latency has the default latency.
+	e := NewNode("e")
+	report, err = NewStrategy(NewQuorumSystemWithReads(e),
+		Sigma{Values: []SigmaRecord{{Quorum: ExprSet{e: true}, Probability: 1}}},
+		Sigma{Values: []SigmaRecord{{Quorum: ExprSet{e: true}, Probability: 1}}}).Report(&rf, &wf)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(report.Metrics.Latency-1000) < float64EqualityThreshold)
+}
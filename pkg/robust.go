@@ -0,0 +1,146 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+)
+
+// RobustOptions describes the scenarios of the workload a robust strategy must withstand, a range of read fractions
+// and/or a set of read fraction distributions.
+type RobustOptions struct {
+	// ReadFractionRange defines a range [lo, hi] of read fractions, every read fraction of the range is a scenario.
+	ReadFractionRange *[2]Fraction
+	// Scenarios defines a set of read fraction distributions, each of them is a scenario.
+	Scenarios []Distribution
+}
+
+// RobustStrategy describes the strategy minimizing the worst case load over a set of scenarios.
+type RobustStrategy struct {
+	Strategy *Strategy
+	// Load is the worst case load over the scenarios.
+	Load float64
+	// Scenarios are the scenarios, the range of read fractions is replaced by its bounds.
+	Scenarios []Distribution
+	// Loads is the load of the strategy in each scenario.
+	Loads []float64
+}
+
+// RobustStrategy returns the strategy minimizing the maximum load over the scenarios, instead of the expected load
+// over a single Distribution.
+//
+// Each scenario k adds the constraint Σ p_k(fr) * l_fr ≤ t, where l_fr is the load at the read fraction fr, and t is
+// minimized. The load of a node is linear in the read fraction, so the worst case over a range of read fractions is
+// at one of its bounds. The ReadFraction, WriteFraction and Optimize options are ignored, and no limit can be set.
+func (qs QuorumSystem) RobustStrategy(robustOptions RobustOptions,
+	strategyOptions StrategyOptions) (RobustStrategy, error) {
+	if strategyOptions.LoadLimit != nil || strategyOptions.NetworkLimit != nil ||
+		strategyOptions.LatencyLimit != nil || strategyOptions.CostLimit != nil {
+		return RobustStrategy{}, fmt.Errorf("limits cannot be set on a robust strategy")
+	}
+
+	scenarios := make([]Distribution, 0)
+
+	if r := robustOptions.ReadFractionRange; r != nil {
+		if r[0] < 0 || r[1] > 1 || r[0] > r[1] {
+			return RobustStrategy{}, fmt.Errorf("the read fraction range must be within [0, 1] with lo <= hi")
+		}
+
+		scenarios = append(scenarios, QuorumDistribution{values: DistributionValues{r[0]: 1}})
+
+		if r[1] != r[0] {
+			scenarios = append(scenarios, QuorumDistribution{values: DistributionValues{r[1]: 1}})
+		}
+	}
+
+	scenarios = append(scenarios, robustOptions.Scenarios...)
+
+	if len(scenarios) == 0 {
+		return RobustStrategy{}, fmt.Errorf("at least one scenario must be given")
+	}
+
+	// The load variables cover every read fraction of every scenario.
+	probabilities := make([]map[Fraction]Probability, 0, len(scenarios))
+	fractions := make(DistributionValues)
+
+	for i := range scenarios {
+		d, err := canonicalize(&scenarios[i])
+
+		if err != nil {
+			return RobustStrategy{}, err
+		}
+
+		for fr := range d {
+			if fr < 0 || fr > 1 {
+				return RobustStrategy{}, fmt.Errorf("read fraction %v must be in [0, 1]", fr)
+			}
+
+			fractions[fr] = 1
+		}
+
+		probabilities = append(probabilities, d)
+	}
+
+	options := strategyOptions
+	options.Optimize = Load
+	options.ReadFraction = QuorumDistribution{values: fractions}
+	options.WriteFraction = nil
+
+	_, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(options))
+
+	if err != nil {
+		return RobustStrategy{}, err
+	}
+
+	problem, err := qs.buildLoadOptimalProblem(Load, rq, wq, d, nil, nil, nil, nil, nil)
+
+	if err != nil {
+		return RobustStrategy{}, err
+	}
+
+	def := problem.def
+
+	for i := range def.Vars {
+		def.Vars[i] = 0
+	}
+
+	t := def.addVariables([2]float64{0, math.Inf(1)})
+	def.Vars[t] = 1
+
+	loadVarIndex := len(problem.readQuorumVars) + len(problem.writeQuorumVars)
+
+	for _, p := range probabilities {
+		row := make([]float64, len(def.Vars)+2)
+		row[0] = math.Inf(-1)
+
+		for i, fr := range problem.fractions {
+			row[1+loadVarIndex+i] = p[fr]
+		}
+
+		row[1+t] = -1
+		def.Objectives = append(def.Objectives, row)
+	}
+
+	solution, err := def.solve()
+
+	if err != nil {
+		return RobustStrategy{}, err
+	}
+
+	strategy := problem.strategy(qs, solution)
+	result := RobustStrategy{Strategy: &strategy, Scenarios: scenarios}
+
+	for i := range scenarios {
+		var wf Distribution
+
+		load, err := strategy.Load(&scenarios[i], &wf)
+
+		if err != nil {
+			return RobustStrategy{}, err
+		}
+
+		result.Loads = append(result.Loads, load)
+		result.Load = math.Max(result.Load, load)
+	}
+
+	return result, nil
+}
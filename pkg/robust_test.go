@@ -0,0 +1,95 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestRobustStrategy(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b, c := NewNodeWithCapacity("a", 4, 1), NewNodeWithCapacity("b", 1, 4), NewNodeWithCapacity("c", 2, 2)
+	majority, err := NewChoose(2, []Expr{a, b, c})
+	assert.NilError(t, err)
+
+	qs := NewQuorumSystemWithReads(majority)
+
+	optimalLoad := func(fr Fraction) (*Strategy, float64) {
+		var rf Distribution = QuorumDistribution{values: DistributionValues{fr: 1}}
+		var wf Distribution
+
+		strategy, err := qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+		assert.NilError(t, err)
+
+		load, err := strategy.Load(&rf, &wf)
+		assert.NilError(t, err)
+
+		return strategy, load
+	}
+
+	loadAt := func(s *Strategy, fr Fraction) float64 {
+		var rf Distribution = QuorumDistribution{values: DistributionValues{fr: 1}}
+		var wf Distribution
+
+		load, err := s.Load(&rf, &wf)
+		assert.NilError(t, err)
+
+		return load
+	}
+
+	// With a single read fraction, the robust strategy is the load optimal strategy.
+	robust, err := qs.RobustStrategy(RobustOptions{ReadFractionRange: &[2]Fraction{0.5, 0.5}}, StrategyOptions{})
+	assert.NilError(t, err)
+
+	_, load := optimalLoad(0.5)
+	assert.Assert(t, math.Abs(robust.Load-load) < float64EqualityThreshold, fmt.Sprintf("Actual:%f", robust.Load))
+	assert.Equal(t, len(robust.Scenarios), 1)
+
+	// Over [0, 1], the worst case is at the bounds, and no strategy optimized for a single bound does better.
+	robust, err = qs.RobustStrategy(RobustOptions{ReadFractionRange: &[2]Fraction{0, 1}}, StrategyOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(robust.Loads), 2)
+	assert.Assert(t, math.Abs(robust.Load-math.Max(robust.Loads[0], robust.Loads[1])) < float64EqualityThreshold)
+
+	for _, fr := range []Fraction{0, 0.25, 0.5, 0.75, 1} {
+		assert.Assert(t, loadAt(robust.Strategy, fr) <= robust.Load+float64EqualityThreshold)
+	}
+
+	for _, fr := range []Fraction{0, 1} {
+		s, load := optimalLoad(fr)
+		worst := math.Max(loadAt(s, 0), loadAt(s, 1))
+
+		assert.Assert(t, robust.Load >= load-float64EqualityThreshold)
+		assert.Assert(t, robust.Load <= worst+float64EqualityThreshold, fmt.Sprintf("Actual:%f > %f", robust.Load, worst))
+	}
+
+	// The scenarios can also be distributions.
+	scenarios := []Distribution{
+		QuorumDistribution{values: DistributionValues{0.1: 1, 0.3: 1}},
+		QuorumDistribution{values: DistributionValues{0.9: 1}},
+	}
+
+	robust, err = qs.RobustStrategy(RobustOptions{Scenarios: scenarios}, StrategyOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(robust.Loads), 2)
+
+	for i := range scenarios {
+		var wf Distribution
+
+		load, err := robust.Strategy.Load(&scenarios[i], &wf)
+		assert.NilError(t, err)
+		assert.Assert(t, load <= robust.Load+float64EqualityThreshold)
+	}
+
+	_, err = qs.RobustStrategy(RobustOptions{}, StrategyOptions{})
+	assert.Error(t, err, "at least one scenario must be given")
+
+	_, err = qs.RobustStrategy(RobustOptions{ReadFractionRange: &[2]Fraction{0.8, 0.2}}, StrategyOptions{})
+	assert.Error(t, err, "the read fraction range must be within [0, 1] with lo <= hi")
+
+	loadLimit := 1.0
+	_, err = qs.RobustStrategy(RobustOptions{Scenarios: scenarios}, StrategyOptions{LoadLimit: &loadLimit})
+	assert.Error(t, err, "limits cannot be set on a robust strategy")
+}
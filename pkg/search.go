@@ -18,6 +18,12 @@ type SearchOptions struct {
 	// F r ∈ R is F-resilient for some integer f if despite removing
 	// any f nodes from r, r is still a read quorum
 	F uint
+	//DomainLevel represents the failure domain level (e.g. "zone") used by D and MinDomains.
+	DomainLevel string
+	//D represents the number of failure domains the quorums must survive.
+	D uint
+	//MinDomains represents the minimum number of failure domains spanned by the quorums.
+	MinDomains uint
 	//TimeoutSecs the number of seconds we can keep searching.
 	TimeoutSecs float64
 	//LoadLimit represents the load limit constraint.
@@ -26,6 +32,8 @@ type SearchOptions struct {
 	NetworkLimit *float64
 	//LatencyLimit represents the latency limit constraint.
 	LatencyLimit *float64
+	//CostLimit represents the cost limit constraint.
+	CostLimit *float64
 }
 
 // initializeSearchOptions returns an initialize function for SearchOptions.
@@ -35,7 +43,11 @@ func initializeSearchOptions(initOptions SearchOptions) func(options *SearchOpti
 		options.LatencyLimit = initOptions.LatencyLimit
 		options.NetworkLimit = initOptions.NetworkLimit
 		options.LoadLimit = initOptions.LoadLimit
+		options.CostLimit = initOptions.CostLimit
 		options.F = initOptions.F
+		options.DomainLevel = initOptions.DomainLevel
+		options.D = initOptions.D
+		options.MinDomains = initOptions.MinDomains
 		options.ReadFraction = initOptions.ReadFraction
 		options.WriteFraction = initOptions.WriteFraction
 		options.TimeoutSecs = initOptions.TimeoutSecs
@@ -69,6 +81,14 @@ func performQuorumSearch(nodes []Expr, opts ...func(options *SearchOptions) erro
 		}
 	}
 
+	if sb.Optimize == Weighted {
+		return SearchResult{}, fmt.Errorf("the search does not support the weighted optimization")
+	}
+
+	if sb.Optimize == TailLatency {
+		return SearchResult{}, fmt.Errorf("the search does not support the tail latency optimization")
+	}
+
 	start := time.Now()
 
 	var optQS *QuorumSystem = nil
@@ -84,6 +104,10 @@ func performQuorumSearch(nodes []Expr, opts ...func(options *SearchOptions) erro
 			return sigma.NetworkLoad(&sb.ReadFraction, &sb.WriteFraction)
 		}
 
+		if sb.Optimize == Cost {
+			return sigma.Cost(&sb.ReadFraction, &sb.WriteFraction)
+		}
+
 		return sigma.Latency(&sb.ReadFraction, &sb.WriteFraction)
 	}
 
@@ -101,9 +125,13 @@ func performQuorumSearch(nodes []Expr, opts ...func(options *SearchOptions) erro
 				LoadLimit:     sb.LoadLimit,
 				NetworkLimit:  sb.NetworkLimit,
 				LatencyLimit:  sb.LatencyLimit,
+				CostLimit:     sb.CostLimit,
 				ReadFraction:  sb.ReadFraction,
 				WriteFraction: sb.WriteFraction,
 				F:             sb.F,
+				DomainLevel:   sb.DomainLevel,
+				D:             sb.D,
+				MinDomains:    sb.MinDomains,
 			}
 
 			strategy, err := qs.Strategy(initializeStrategyOptions(stratOpts))
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestPartitions(t *testing.T) {
@@ -151,12 +152,12 @@ func TestDupFreePartitions(t *testing.T) {
 }
 
 func TestSearch(t *testing.T) {
-	a, b, c, e, d, f := NewNodeWithCapacityAndLatency("a", 1, 1, 2),
-		NewNodeWithCapacityAndLatency("b", 1, 1, 1),
-		NewNodeWithCapacityAndLatency("c", 1, 1, 2),
-		NewNodeWithCapacityAndLatency("d", 2, 2, 1),
-		NewNodeWithCapacityAndLatency("e", 1, 1, 2),
-		NewNodeWithCapacityAndLatency("f", 2, 2, 1)
+	a, b, c, e, d, f := NewNodeWithCapacityAndLatency("a", 1, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("b", 1, 1, 1*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 1, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("d", 2, 2, 1*time.Millisecond),
+		NewNodeWithCapacityAndLatency("e", 1, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("f", 2, 2, 1*time.Millisecond)
 
 	for _, fr := range []float64{0, 0.5, 1} {
 		result, err := Search(SearchOptions{Optimize: Load, ReadFraction: QuorumDistribution{DistributionValues{fr: 1.0}}}, a, b, c)
@@ -0,0 +1,276 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// maxSelectionCandidates is the maximum number of candidates of SelectNodes, which enumerates their subsets.
+const maxSelectionCandidates = 20
+
+// QuorumFamily returns the read Expr of a family of quorum systems over the given nodes, e.g. a majority or a grid.
+// The write Expr is its dual. A family that cannot be built over the nodes returns a FamilyNotApplicableError.
+type QuorumFamily func(nodes []Node) (Expr, error)
+
+// FamilyNotApplicableError is the error of a QuorumFamily that cannot be built over the given nodes, e.g. a grid over
+// a prime number of nodes. SelectNodes skips the subsets of the candidates with this error.
+type FamilyNotApplicableError struct {
+	Reason string
+}
+
+// Error returns the reason why the family cannot be built.
+func (e FamilyNotApplicableError) Error() string {
+	return e.Reason
+}
+
+// MajorityFamily is the QuorumFamily where the read and write quorums are the majorities of the nodes.
+func MajorityFamily(nodes []Node) (Expr, error) {
+	return NewChoose(len(nodes)/2+1, nodesToExprs(nodes))
+}
+
+// GridFamily is the QuorumFamily where the nodes are arranged, in order, in the most square grid with at least two
+// rows and two columns: a read quorum is a row, a write quorum is a node of each row.
+func GridFamily(nodes []Node) (Expr, error) {
+	n := len(nodes)
+
+	for rows := int(math.Sqrt(float64(n))); rows >= 2; rows-- {
+		if n%rows != 0 {
+			continue
+		}
+
+		columns := n / rows
+		grid := make([]Expr, 0, rows)
+
+		for r := 0; r < rows; r++ {
+			grid = append(grid, And{Es: nodesToExprs(nodes[r*columns : (r+1)*columns])})
+		}
+
+		return Or{Es: grid}, nil
+	}
+
+	return nil, FamilyNotApplicableError{Reason: fmt.Sprintf("%d nodes cannot be arranged in a grid", n)}
+}
+
+// SelectionOptions describes the options of the selection of nodes among candidates.
+type SelectionOptions struct {
+	// Family defines the quorum system built over the selected nodes.
+	Family QuorumFamily
+	// Costs defines the cost of deploying each candidate, indexed by node name. A candidate without cost costs 1, so
+	// that the smallest subsets are preferred.
+	Costs map[string]float64
+	// Resilience defines the minimal resilience of the quorum system.
+	Resilience uint
+	// StrategyOptions defines the workload and the limits (e.g. LoadLimit and LatencyLimit) the strategy of the
+	// selected nodes must meet. When Optimize is set, it also breaks the ties between the subsets of the same cost.
+	StrategyOptions StrategyOptions
+}
+
+// SelectionResult describes the selected nodes, their quorum system and its strategy.
+type SelectionResult struct {
+	// Nodes are the selected nodes, sorted by name.
+	Nodes        []Node
+	Cost         float64
+	QuorumSystem QuorumSystem
+	Strategy     *Strategy
+}
+
+// SelectNodes returns the cheapest subset of the candidates whose quorum system, built by the family, meets the
+// resilience and has a strategy within the limits of the StrategyOptions. Unlike Search, the nodes are not fixed up
+// front but the shape of the quorum system is. The subsets are enumerated, so at most 20 candidates are supported.
+func SelectNodes(options SelectionOptions, candidates ...Node) (SelectionResult, error) {
+	if options.Family == nil {
+		return SelectionResult{}, fmt.Errorf("a quorum family must be given")
+	}
+
+	if len(candidates) == 0 {
+		return SelectionResult{}, fmt.Errorf("at least one candidate must be given")
+	}
+
+	if len(candidates) > maxSelectionCandidates {
+		return SelectionResult{}, fmt.Errorf("at most %d candidates are supported", maxSelectionCandidates)
+	}
+
+	if options.StrategyOptions.Optimize == Weighted {
+		return SelectionResult{}, fmt.Errorf("the selection does not support the weighted optimization")
+	}
+
+	sorted := append([]Node{}, candidates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	costs := make([]float64, len(sorted))
+	names := make(map[string]bool)
+
+	for i, n := range sorted {
+		if names[n.Name] {
+			return SelectionResult{}, fmt.Errorf("candidate %s is given more than once", n.Name)
+		}
+
+		names[n.Name] = true
+		costs[i] = 1
+
+		if cost, ok := options.Costs[n.Name]; ok {
+			if cost < 0 {
+				return SelectionResult{}, fmt.Errorf("the cost of candidate %s must be >= 0", n.Name)
+			}
+
+			costs[i] = cost
+		}
+	}
+
+	for name := range options.Costs {
+		if !names[name] {
+			return SelectionResult{}, fmt.Errorf("candidate %s not found", name)
+		}
+	}
+
+	// The subsets are bit masks over the sorted candidates, visited by increasing cost and size.
+	subsets := make([]uint32, 0, 1<<len(sorted)-1)
+	subsetCosts := make(map[uint32]float64)
+
+	for mask := uint32(1); mask < 1<<len(sorted); mask++ {
+		cost := 0.0
+
+		for i := range sorted {
+			if mask&(1<<i) != 0 {
+				cost += costs[i]
+			}
+		}
+
+		subsets = append(subsets, mask)
+		subsetCosts[mask] = cost
+	}
+
+	sort.SliceStable(subsets, func(i, j int) bool {
+		ci, cj := subsetCosts[subsets[i]], subsetCosts[subsets[j]]
+
+		if math.Abs(ci-cj) > probabilityThreshold {
+			return ci < cj
+		}
+
+		return bits.OnesCount32(subsets[i]) < bits.OnesCount32(subsets[j])
+	})
+
+	// The options are validated once over the candidates, so that an error on a subset means that it is infeasible.
+	strategyOptions := options.StrategyOptions
+	candidateSet := make(NodeSet)
+
+	for _, n := range sorted {
+		candidateSet[n] = true
+	}
+
+	validated := strategyOptions
+
+	if _, err := validateStrategyOptions(&validated, candidateSet); err != nil {
+		return SelectionResult{}, err
+	}
+
+	var best *SelectionResult
+	var bestMetric float64
+
+	for _, mask := range subsets {
+		cost := subsetCosts[mask]
+
+		// The subsets are sorted by cost: the cheapest feasible subsets have been evaluated.
+		if best != nil && cost > best.Cost+probabilityThreshold {
+			break
+		}
+
+		nodes := make([]Node, 0)
+
+		for i, n := range sorted {
+			if mask&(1<<i) != 0 {
+				nodes = append(nodes, n)
+			}
+		}
+
+		reads, err := options.Family(nodes)
+
+		if errors.As(err, &FamilyNotApplicableError{}) {
+			continue
+		}
+
+		if err != nil {
+			return SelectionResult{}, err
+		}
+
+		qs := NewQuorumSystemWithReads(reads)
+
+		if qs.Resilience() < options.Resilience {
+			continue
+		}
+
+		// The options are valid: prepareStrategy can only fail when no quorum meets the resilience targets.
+		sb, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(strategyOptions))
+
+		if err != nil {
+			continue
+		}
+
+		strategy, err := qs.optimalStrategy(sb, rq, wq, d)
+
+		if errors.Is(err, errNoOptimalStrategy) {
+			continue
+		}
+
+		if err != nil {
+			return SelectionResult{}, err
+		}
+
+		result := SelectionResult{Nodes: nodes, Cost: cost, QuorumSystem: qs, Strategy: strategy}
+
+		if strategyOptions.Optimize == "" {
+			return result, nil
+		}
+
+		metric, err := strategy.metric(strategyOptions.Optimize, validated.Percentile, &strategyOptions.ReadFraction,
+			&strategyOptions.WriteFraction)
+
+		if err != nil {
+			return SelectionResult{}, err
+		}
+
+		if best == nil || metric < bestMetric {
+			best = &result
+			bestMetric = metric
+		}
+	}
+
+	if best == nil {
+		return SelectionResult{}, fmt.Errorf("no subset of the candidates meets the targets")
+	}
+
+	return *best, nil
+}
+
+// metric returns the value of the optimized metric of the strategy given a read and write Distribution.
+func (s Strategy) metric(optimize OptimizeType, percentile float64, rf *Distribution,
+	wf *Distribution) (float64, error) {
+	switch optimize {
+	case Load:
+		return s.Load(rf, wf)
+	case Network:
+		return s.NetworkLoad(rf, wf)
+	case Latency:
+		return s.Latency(rf, wf)
+	case Cost:
+		return s.Cost(rf, wf)
+	case TailLatency:
+		return s.LatencyPercentile(percentile, rf, wf)
+	}
+
+	return 0, fmt.Errorf("unknown optimization %s", optimize)
+}
+
+// nodesToExprs returns the nodes as a list of Expr.
+func nodesToExprs(nodes []Node) []Expr {
+	exprs := make([]Expr, 0, len(nodes))
+
+	for _, n := range nodes {
+		exprs = append(exprs, n)
+	}
+
+	return exprs
+}
@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSelectNodes(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	candidates := make([]Node, 0)
+
+	for i := 1; i <= 5; i++ {
+		candidates = append(candidates, NewNodeWithLatency(fmt.Sprintf("n%d", i), time.Duration(i)*time.Millisecond))
+	}
+
+	loadLimit := 0.7
+	options := SelectionOptions{
+		Family: MajorityFamily,
+		StrategyOptions: StrategyOptions{
+			Optimize:  Latency,
+			LoadLimit: &loadLimit,
+			ReadFraction: QuorumDistribution{
+				values: map[Fraction]Weight{1: 1}},
+		},
+	}
+
+	// A majority of three nodes has a load of 2/3, the fastest three nodes are selected.
+	result, err := SelectNodes(options, candidates...)
+	assert.NilError(t, err)
+	assert.Equal(t, nodeNames(result.Nodes), "n1,n2,n3")
+	assert.Assert(t, math.Abs(result.Cost-3) < float64EqualityThreshold)
+
+	load, err := result.Strategy.Load(&options.StrategyOptions.ReadFraction, &options.StrategyOptions.WriteFraction)
+	assert.NilError(t, err)
+	assert.Assert(t, load <= loadLimit+float64EqualityThreshold, fmt.Sprintf("Actual:%f", load))
+
+	// An expensive candidate is avoided.
+	options.Costs = map[string]float64{"n1": 10}
+	result, err = SelectNodes(options, candidates...)
+	assert.NilError(t, err)
+	assert.Equal(t, nodeNames(result.Nodes), "n2,n3,n4")
+
+	// Tolerating two failures takes a majority of five nodes.
+	options.Resilience = 2
+	result, err = SelectNodes(options, candidates...)
+	assert.NilError(t, err)
+	assert.Equal(t, len(result.Nodes), 5)
+	assert.Assert(t, math.Abs(result.Cost-14) < float64EqualityThreshold)
+
+	// A 2x2 grid reads a row, the load of a node is at most 1/2.
+	options.Family = GridFamily
+	options.Costs = nil
+	options.Resilience = 0
+
+	result, err = SelectNodes(options, candidates...)
+	assert.NilError(t, err)
+	assert.Equal(t, nodeNames(result.Nodes), "n1,n2,n3,n4")
+	assert.Equal(t, result.QuorumSystem.reads.String(), "((n1 * n2) + (n3 * n4))")
+
+	_, err = GridFamily(candidates)
+	assert.Error(t, err, "5 nodes cannot be arranged in a grid")
+
+	loadLimit = 0.1
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "no subset of the candidates meets the targets")
+
+	_, err = SelectNodes(SelectionOptions{}, candidates...)
+	assert.Error(t, err, "a quorum family must be given")
+
+	options.Costs = map[string]float64{"x": 1}
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "candidate x not found")
+
+	// Invalid options and the errors of the family are reported instead of skipping every subset.
+	options.Costs = nil
+	options.StrategyOptions = StrategyOptions{Optimize: TailLatency, Percentile: 1.5}
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "percentile must be in (0, 1)")
+
+	options.StrategyOptions = StrategyOptions{Optimize: Load}
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "either readFraction or writeFraction must be given")
+
+	options.StrategyOptions.ReadFraction = QuorumDistribution{values: map[Fraction]Weight{1: 1}}
+	options.StrategyOptions.D = 1
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "a failure domain level must be set when D or MinDomains are set")
+
+	options.StrategyOptions.D = 0
+	options.Family = func(nodes []Node) (Expr, error) { return nil, fmt.Errorf("broken family") }
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "broken family")
+
+	// The subsets whose linear program is infeasible are skipped, the other errors of the Solver are reported.
+	defer SetSolver(nil)
+
+	options.Family = MajorityFamily
+	SetSolver(failingSolver{err: ErrInfeasible})
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "no subset of the candidates meets the targets")
+
+	SetSolver(failingSolver{err: fmt.Errorf("%w of 10 pivots", ErrIterationLimit)})
+	_, err = SelectNodes(options, candidates...)
+	assert.Error(t, err, "the solver reached its iteration limit of 10 pivots")
+}
+
+// nodeNames returns the names of the nodes joined by ",".
+func nodeNames(nodes []Node) string {
+	names := ""
+
+	for i, n := range nodes {
+		if i > 0 {
+			names += ","
+		}
+		names += n.Name
+	}
+
+	return names
+}
@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NodeSensitivity describes how the optimal load reacts to the capacity of a node.
+type NodeSensitivity struct {
+	Node Node
+	// Binding is true when the load of the node equals the optimal load for at least one read fraction.
+	Binding bool
+	// ShadowPrice is the decrease of the optimal load per unit of load the node could take above the others.
+	// It is 0 when adding capacity to the node alone does not reduce the optimal load.
+	ShadowPrice float64
+	// ReadCapacityGradient is the change of the optimal load per unit of extra read capacity on the node.
+	ReadCapacityGradient float64
+	// WriteCapacityGradient is the change of the optimal load per unit of extra write capacity on the node.
+	WriteCapacityGradient float64
+}
+
+// QuorumSensitivity describes the reduced cost of a quorum in the optimal strategy.
+type QuorumSensitivity struct {
+	Quorum      ExprSet
+	Probability Probability
+	// ReducedCost is the increase of the optimal load per unit of probability forced on the quorum.
+	ReducedCost float64
+}
+
+// SensitivityReport describes the sensitivity of the load optimal strategy to the node capacities.
+type SensitivityReport struct {
+	Strategy     *Strategy
+	Load         float64
+	Nodes        []NodeSensitivity
+	ReadQuorums  []QuorumSensitivity
+	WriteQuorums []QuorumSensitivity
+}
+
+// Sensitivity returns the load optimal strategy with the dual values and reduced costs of its linear program:
+// which node load constraints bind, and how much the optimal load changes per unit of extra capacity on each node.
+// The nodes are sorted by name.
+func (qs QuorumSystem) Sensitivity(strategyOptions StrategyOptions) (SensitivityReport, error) {
+	sb, rq, wq, d, err := qs.prepareStrategy(initializeStrategyOptions(strategyOptions))
+
+	if err != nil {
+		return SensitivityReport{}, err
+	}
+
+	if sb.Optimize != Load {
+		return SensitivityReport{}, fmt.Errorf("sensitivity analysis is only available when optimizing for load")
+	}
+
+	problem, err := qs.buildLoadOptimalProblem(sb.Optimize, rq, wq, d,
+		sb.LoadLimit, sb.NetworkLimit, sb.LatencyLimit, sb.CostLimit, nil)
+
+	if err != nil {
+		return SensitivityReport{}, err
+	}
+
+	solution, err := problem.def.solve()
+
+	if err != nil {
+		return SensitivityReport{}, err
+	}
+
+	strategy := problem.strategy(qs, solution)
+
+	report := SensitivityReport{Strategy: &strategy, Load: solution.Objective}
+
+	const threshold = 1e-9
+
+	nodes := qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	for _, n := range nodes {
+		ns := NodeSensitivity{Node: n}
+
+		for i, fr := range problem.fractions {
+			row := problem.def.Objectives[problem.loadRows[i][n]]
+			activity := 0.0
+
+			for j, v := range solution.Primal {
+				activity += row[1+j] * v
+			}
+
+			if math.Abs(activity) <= threshold {
+				ns.Binding = true
+			}
+
+			// The dual value of a load constraint is never positive, its absolute value does not depend on the sign
+			// convention of the solver.
+			multiplier := math.Abs(solution.RowDuals[problem.loadRows[i][n]])
+			ns.ShadowPrice += multiplier
+
+			readCapacity := n.GetReadCapacity()
+			writeCapacity := n.GetWriteCapacity()
+
+			ns.ReadCapacityGradient -= multiplier * fr * strategy.nodeToReadProbability[n] / (readCapacity * readCapacity)
+			ns.WriteCapacityGradient -= multiplier * (1 - fr) * strategy.nodeToWriteProbability[n] / (writeCapacity * writeCapacity)
+		}
+
+		report.Nodes = append(report.Nodes, ns)
+	}
+
+	for _, v := range problem.readQuorumVars {
+		report.ReadQuorums = append(report.ReadQuorums, QuorumSensitivity{
+			Quorum: v.Quorum, Probability: solution.Primal[v.Index], ReducedCost: solution.ReducedCosts[v.Index]})
+	}
+
+	for _, v := range problem.writeQuorumVars {
+		report.WriteQuorums = append(report.WriteQuorums, QuorumSensitivity{
+			Quorum: v.Quorum, Probability: solution.Primal[v.Index], ReducedCost: solution.ReducedCosts[v.Index]})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestSensitivity(t *testing.T) {
+	a := NewNodeWithCapacity("a", 1, 1)
+	b := NewNodeWithCapacity("b", 2, 1)
+
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	report, err := qs.Sensitivity(StrategyOptions{
+		Optimize:     Load,
+		ReadFraction: QuorumDistribution{values: DistributionValues{1: 1}},
+	})
+	assert.NilError(t, err)
+
+	// The optimal load is 1 / (cap(a) + cap(b)), and its derivative on both read capacities is -1 / (cap(a) + cap(b))^2.
+	assert.Assert(t, math.Abs(report.Load-1.0/3) < 1e-6)
+	assert.Equal(t, len(report.Nodes), 2)
+	assert.Equal(t, report.Nodes[0].Node, a)
+	assert.Equal(t, report.Nodes[1].Node, b)
+
+	expectedShadowPrices := []float64{1.0 / 3, 2.0 / 3}
+
+	for i, n := range report.Nodes {
+		assert.Assert(t, n.Binding)
+		assert.Assert(t, math.Abs(n.ShadowPrice-expectedShadowPrices[i]) < 1e-6)
+		assert.Assert(t, math.Abs(n.ReadCapacityGradient+1.0/9) < 1e-6)
+		assert.Assert(t, math.Abs(n.WriteCapacityGradient) < 1e-6)
+	}
+
+	for _, q := range report.ReadQuorums {
+		assert.Assert(t, q.Probability > 0)
+		assert.Assert(t, math.Abs(q.ReducedCost) < 1e-6)
+	}
+
+	// Each read fraction has its own load, weighted by its probability.
+	report, err = qs.Sensitivity(StrategyOptions{
+		Optimize:     Load,
+		ReadFraction: QuorumDistribution{values: DistributionValues{1: 1, 0: 1}},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(report.Load-(0.5/3+0.5)) < 1e-6)
+
+	_, err = qs.Sensitivity(StrategyOptions{
+		Optimize:     Network,
+		ReadFraction: QuorumDistribution{values: DistributionValues{1: 1}},
+	})
+	assert.Error(t, err, "sensitivity analysis is only available when optimizing for load")
+}
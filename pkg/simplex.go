@@ -0,0 +1,395 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// simplexEpsilon is the tolerance of the pivoting rules of the simplex.
+	simplexEpsilon = 1e-9
+	// simplexFeasibilityThreshold is the maximum sum of the artificial variables of a feasible program.
+	simplexFeasibilityThreshold = 1e-7
+	// defaultSimplexIterations is the default maximum number of pivots of each phase of the simplex.
+	defaultSimplexIterations = 100000
+	// blandIterations is the number of pivots after which the simplex switches to Bland's rule, which cannot cycle.
+	blandIterations = 5000
+)
+
+// simplexStatus is the outcome of a phase of the simplex.
+type simplexStatus int
+
+const (
+	simplexOptimal simplexStatus = iota
+	simplexUnbounded
+	simplexIterationLimit
+)
+
+// SimplexSolver is the pure-Go Solver, a dense two-phase simplex. It needs neither cgo nor the CLP library, and its
+// results can be cross-checked with CLPSolver.
+type SimplexSolver struct {
+	// MaxIterations defines the maximum number of pivots of each phase, 100000 by default.
+	MaxIterations int
+}
+
+// simplexColumn describes how a variable of the LinearProgram is expressed with the non-negative columns of the
+// tableau: x = offset + scale * x_column - x_negative, where negative is -1 unless the variable is free.
+type simplexColumn struct {
+	offset   float64
+	scale    float64
+	column   int
+	negative int
+}
+
+// simplexRow describes a row of the standard form, Σ coefficients * x ≤ rhs or = rhs.
+type simplexRow struct {
+	coefficients map[int]float64
+	rhs          float64
+	equality     bool
+	// source is the index of the row of the LinearProgram, -1 for the upper bound of a variable.
+	source int
+	// sign is -1 when the row is the lower bound of the source row, negated.
+	sign float64
+}
+
+// simplexTableau is the dense tableau of the standard form. The columns are the variables, the slacks of the
+// inequalities, one artificial variable per row and the right-hand side. The last row holds the reduced costs.
+type simplexTableau struct {
+	rows       [][]float64
+	basis      []int
+	artificial int
+	width      int
+}
+
+// Solve minimizes the LinearProgram.
+//
+// The variables are shifted and mirrored to be non-negative, a free variable is split in two, and the rows are
+// turned into inequalities with slacks and equalities. Phase 1 finds a feasible basis by minimizing the sum of the
+// artificial variables, phase 2 minimizes the objective from it. The duals are read from the reduced costs of the
+// artificial variables.
+func (s SimplexSolver) Solve(lp LinearProgram) (LinearProgramSolution, error) {
+	maxIterations := s.MaxIterations
+
+	if maxIterations <= 0 {
+		maxIterations = defaultSimplexIterations
+	}
+
+	n := len(lp.Objective)
+
+	if len(lp.Bounds) != n {
+		return LinearProgramSolution{}, fmt.Errorf("%d bounds given for %d variables", len(lp.Bounds), n)
+	}
+
+	columns := make([]simplexColumn, n)
+	rows := make([]simplexRow, 0)
+	numColumns := 0
+
+	for j, b := range lp.Bounds {
+		lo, hi := b[0], b[1]
+
+		if lo > hi {
+			return LinearProgramSolution{}, fmt.Errorf("%w: the bounds of variable %d are inverted", ErrInfeasible, j)
+		}
+
+		switch {
+		case !math.IsInf(lo, -1):
+			columns[j] = simplexColumn{offset: lo, scale: 1, column: numColumns, negative: -1}
+			numColumns++
+
+			if !math.IsInf(hi, 1) {
+				rows = append(rows, simplexRow{
+					coefficients: map[int]float64{columns[j].column: 1}, rhs: hi - lo, source: -1, sign: 1})
+			}
+		case !math.IsInf(hi, 1):
+			columns[j] = simplexColumn{offset: hi, scale: -1, column: numColumns, negative: -1}
+			numColumns++
+		default:
+			columns[j] = simplexColumn{scale: 1, column: numColumns, negative: numColumns + 1}
+			numColumns += 2
+		}
+	}
+
+	for i, r := range lp.Rows {
+		if len(r) != n+2 {
+			return LinearProgramSolution{}, fmt.Errorf("row %d has %d values, %d expected", i, len(r), n+2)
+		}
+
+		lo, hi := r[0], r[n+1]
+		coefficients := make(map[int]float64)
+		shift := 0.0
+
+		for j, c := range columns {
+			v := r[j+1]
+
+			if v == 0 {
+				continue
+			}
+
+			shift += v * c.offset
+			coefficients[c.column] += v * c.scale
+
+			if c.negative >= 0 {
+				coefficients[c.negative] -= v
+			}
+		}
+
+		if lo == hi {
+			rows = append(rows, simplexRow{coefficients: coefficients, rhs: hi - shift, equality: true, source: i, sign: 1})
+			continue
+		}
+
+		if !math.IsInf(hi, 1) {
+			rows = append(rows, simplexRow{coefficients: coefficients, rhs: hi - shift, source: i, sign: 1})
+		}
+
+		if !math.IsInf(lo, -1) {
+			negated := make(map[int]float64, len(coefficients))
+
+			for k, v := range coefficients {
+				negated[k] = -v
+			}
+
+			rows = append(rows, simplexRow{coefficients: negated, rhs: shift - lo, source: i, sign: -1})
+		}
+	}
+
+	cost := make([]float64, numColumns)
+
+	for j, c := range columns {
+		cost[c.column] += lp.Objective[j] * c.scale
+
+		if c.negative >= 0 {
+			cost[c.negative] -= lp.Objective[j]
+		}
+	}
+
+	tableau, rowSigns := newSimplexTableau(rows, numColumns)
+
+	// Phase 1: minimize the sum of the artificial variables.
+	phase1 := make([]float64, tableau.width)
+
+	for i := range rows {
+		phase1[tableau.artificial+i] = 1
+	}
+
+	// The sum of the artificial variables is bounded below by 0, phase 1 cannot be unbounded.
+	if status := tableau.run(phase1, tableau.width-1, maxIterations); status == simplexIterationLimit {
+		return LinearProgramSolution{}, fmt.Errorf("%w of %d pivots", ErrIterationLimit, maxIterations)
+	}
+
+	if -tableau.rows[len(rows)][tableau.width-1] > simplexFeasibilityThreshold {
+		return LinearProgramSolution{}, ErrInfeasible
+	}
+
+	tableau.removeArtificials()
+
+	// Phase 2: minimize the objective, the artificial variables cannot enter the basis anymore.
+	phase2 := make([]float64, tableau.width)
+	copy(phase2, cost)
+
+	switch tableau.run(phase2, tableau.artificial, maxIterations) {
+	case simplexUnbounded:
+		return LinearProgramSolution{}, ErrUnbounded
+	case simplexIterationLimit:
+		return LinearProgramSolution{}, fmt.Errorf("%w of %d pivots", ErrIterationLimit, maxIterations)
+	}
+
+	values := make([]float64, numColumns)
+
+	for i, b := range tableau.basis {
+		if b < numColumns {
+			values[b] = tableau.rows[i][tableau.width-1]
+		}
+	}
+
+	solution := LinearProgramSolution{
+		Primal:       make([]float64, n),
+		RowDuals:     make([]float64, len(lp.Rows)),
+		ReducedCosts: make([]float64, n),
+	}
+
+	for j, c := range columns {
+		solution.Primal[j] = c.offset + c.scale*values[c.column]
+
+		if c.negative >= 0 {
+			solution.Primal[j] -= values[c.negative]
+		}
+
+		solution.Objective += lp.Objective[j] * solution.Primal[j]
+	}
+
+	// The reduced cost of an artificial variable is minus the dual of its row.
+	for i, r := range rows {
+		if r.source >= 0 {
+			solution.RowDuals[r.source] -= r.sign * rowSigns[i] * tableau.rows[len(rows)][tableau.artificial+i]
+		}
+	}
+
+	for j := range columns {
+		solution.ReducedCosts[j] = lp.Objective[j]
+
+		for i, r := range lp.Rows {
+			solution.ReducedCosts[j] -= r[j+1] * solution.RowDuals[i]
+		}
+	}
+
+	return solution, nil
+}
+
+// newSimplexTableau returns the tableau of the rows, whose basis is the artificial variables, together with the
+// sign of each row: a row with a negative right-hand side is negated.
+func newSimplexTableau(rows []simplexRow, numColumns int) (simplexTableau, []float64) {
+	numSlacks := 0
+
+	for _, r := range rows {
+		if !r.equality {
+			numSlacks++
+		}
+	}
+
+	t := simplexTableau{
+		rows:       make([][]float64, len(rows)+1),
+		basis:      make([]int, len(rows)),
+		artificial: numColumns + numSlacks,
+		width:      numColumns + numSlacks + len(rows) + 1,
+	}
+	signs := make([]float64, len(rows))
+	slack := numColumns
+
+	for i, r := range rows {
+		row := make([]float64, t.width)
+
+		for k, v := range r.coefficients {
+			row[k] = v
+		}
+
+		if !r.equality {
+			row[slack] = 1
+			slack++
+		}
+
+		row[t.width-1] = r.rhs
+		signs[i] = 1
+
+		if r.rhs < 0 {
+			for k := range row {
+				row[k] = -row[k]
+			}
+
+			signs[i] = -1
+		}
+
+		row[t.artificial+i] = 1
+		t.basis[i] = t.artificial + i
+		t.rows[i] = row
+	}
+
+	return t, signs
+}
+
+// pivot makes the column the basic variable of the row.
+func (t *simplexTableau) pivot(row int, column int) {
+	p := t.rows[row][column]
+
+	for k := range t.rows[row] {
+		t.rows[row][k] /= p
+	}
+
+	for i := range t.rows {
+		f := t.rows[i][column]
+
+		if i == row || f == 0 {
+			continue
+		}
+
+		for k := range t.rows[i] {
+			t.rows[i][k] -= f * t.rows[row][k]
+		}
+	}
+
+	t.basis[row] = column
+}
+
+// run minimizes the cost from the current basis, only the columns before the limit can enter the basis. It returns
+// whether the minimum is reached, the cost is unbounded or the maximum number of iterations is reached first.
+func (t *simplexTableau) run(cost []float64, limit int, maxIterations int) simplexStatus {
+	m := len(t.basis)
+	reduced := make([]float64, t.width)
+	copy(reduced, cost)
+
+	for i, b := range t.basis {
+		if cost[b] == 0 {
+			continue
+		}
+
+		for k := range reduced {
+			reduced[k] -= cost[b] * t.rows[i][k]
+		}
+	}
+
+	t.rows[m] = reduced
+
+	for iteration := 0; ; iteration++ {
+		// Dantzig's rule picks the most negative reduced cost, Bland's rule the first negative one.
+		bland := iteration > blandIterations
+		column := -1
+		best := -simplexEpsilon
+
+		for k := 0; k < limit; k++ {
+			if t.rows[m][k] < best {
+				column = k
+				best = t.rows[m][k]
+
+				if bland {
+					break
+				}
+			}
+		}
+
+		if column < 0 {
+			return simplexOptimal
+		}
+
+		if iteration == maxIterations {
+			return simplexIterationLimit
+		}
+
+		row := -1
+		ratio := math.Inf(1)
+
+		for i := 0; i < m; i++ {
+			if t.rows[i][column] <= simplexEpsilon {
+				continue
+			}
+
+			r := t.rows[i][t.width-1] / t.rows[i][column]
+
+			if r < ratio-simplexEpsilon || (r < ratio+simplexEpsilon && row >= 0 && t.basis[i] < t.basis[row]) {
+				ratio = r
+				row = i
+			}
+		}
+
+		if row < 0 {
+			return simplexUnbounded
+		}
+
+		t.pivot(row, column)
+	}
+}
+
+// removeArtificials pivots the artificial variables left in the basis at zero out of it, when possible.
+func (t *simplexTableau) removeArtificials() {
+	for i, b := range t.basis {
+		if b < t.artificial {
+			continue
+		}
+
+		for k := 0; k < t.artificial; k++ {
+			if math.Abs(t.rows[i][k]) > simplexEpsilon {
+				t.pivot(i, k)
+				break
+			}
+		}
+	}
+}
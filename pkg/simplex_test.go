@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"errors"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestSimplexSolver(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	inf := math.Inf(1)
+	solver := SimplexSolver{}
+
+	// maximize x + y, i.e. minimize -x - y, with x + 2y ≤ 4, 3x + y ≤ 6: x = 1.6, y = 1.2.
+	solution, err := solver.Solve(LinearProgram{
+		Objective: []float64{-1, -1},
+		Bounds:    [][2]float64{{0, inf}, {0, inf}},
+		Rows:      [][]float64{{-inf, 1, 2, 4}, {-inf, 3, 1, 6}},
+	})
+	assert.NilError(t, err)
+
+	assert.Assert(t, math.Abs(solution.Primal[0]-1.6) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.Primal[1]-1.2) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.Objective+2.8) <= float64EqualityThreshold)
+
+	// Loosening x + 2y ≤ 4 by one decreases the objective by 0.4, loosening 3x + y ≤ 6 by 0.2.
+	assert.Assert(t, math.Abs(solution.RowDuals[0]+0.4) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.RowDuals[1]+0.2) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.ReducedCosts[0]) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.ReducedCosts[1]) <= float64EqualityThreshold)
+
+	// minimize x - y with a free x, -2 ≤ y ≤ 3, and the ranged row 1 ≤ x + y ≤ 2: x = -2, y = 3.
+	solution, err = solver.Solve(LinearProgram{
+		Objective: []float64{1, -1},
+		Bounds:    [][2]float64{{-inf, inf}, {-2, 3}},
+		Rows:      [][]float64{{1, 1, 1, 2}},
+	})
+	assert.NilError(t, err)
+
+	assert.Assert(t, math.Abs(solution.Primal[0]+2) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.Primal[1]-3) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.Objective+5) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.RowDuals[0]-1) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(solution.ReducedCosts[1]+2) <= float64EqualityThreshold)
+
+	// minimize x + y with x + y = 1, x ≤ 0.25 and y ≤ 0.5 is infeasible.
+	_, err = solver.Solve(LinearProgram{
+		Objective: []float64{1, 1},
+		Bounds:    [][2]float64{{0, 0.25}, {0, 0.5}},
+		Rows:      [][]float64{{1, 1, 1, 1}},
+	})
+	assert.Assert(t, errors.Is(err, ErrInfeasible))
+
+	// minimize -x with x ≥ 0 is unbounded.
+	_, err = solver.Solve(LinearProgram{
+		Objective: []float64{-1},
+		Bounds:    [][2]float64{{0, inf}},
+	})
+	assert.Assert(t, errors.Is(err, ErrUnbounded))
+
+	// The iteration limit is reported as such, both in phase 1 and in phase 2: x + y = 1, x - y = 0 takes 2 pivots in
+	// phase 1.
+	_, err = SimplexSolver{MaxIterations: 1}.Solve(LinearProgram{
+		Objective: []float64{-1, -1},
+		Bounds:    [][2]float64{{0, inf}, {0, inf}},
+		Rows:      [][]float64{{1, 1, 1, 1}, {0, 1, -1, 0}},
+	})
+	assert.Error(t, err, "the solver reached its iteration limit of 1 pivots")
+	assert.Assert(t, errors.Is(err, ErrIterationLimit))
+
+	// Klee-Minty in equality form: phase 1 takes the 3 pivots of the slacks s_i, phase 2 takes 5 pivots from the
+	// origin to the optimum x_3 = 125.
+	kleeMinty := LinearProgram{
+		Objective: []float64{0, 0, 0, -4, -2, -1},
+		Bounds:    [][2]float64{{0, inf}, {0, inf}, {0, inf}, {0, inf}, {0, inf}, {0, inf}},
+		Rows: [][]float64{
+			{5, 100, 0, 0, 1, 0, 0, 5},
+			{25, 0, 100, 0, 4, 1, 0, 25},
+			{125, 0, 0, 100, 8, 4, 1, 125},
+		},
+	}
+
+	_, err = SimplexSolver{MaxIterations: 4}.Solve(kleeMinty)
+	assert.Assert(t, errors.Is(err, ErrIterationLimit))
+
+	solution, err = SimplexSolver{MaxIterations: 5}.Solve(kleeMinty)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(solution.Objective+125) <= float64EqualityThreshold)
+
+	_, err = solver.Solve(LinearProgram{
+		Objective: []float64{1},
+		Bounds:    [][2]float64{{1, 0}},
+	})
+	assert.Error(t, err, "the linear program is infeasible: the bounds of variable 0 are inverted")
+	assert.Assert(t, errors.Is(err, ErrInfeasible))
+
+	_, err = solver.Solve(LinearProgram{
+		Objective: []float64{1},
+		Bounds:    [][2]float64{{0, 1}},
+		Rows:      [][]float64{{0, 1}},
+	})
+	assert.Error(t, err, "row 0 has 2 values, 3 expected")
+	assert.Assert(t, !errors.Is(err, ErrInfeasible))
+}
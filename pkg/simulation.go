@@ -0,0 +1,306 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// FailureModel wraps the methods for sampling node failures in a Monte Carlo simulation.
+type FailureModel interface {
+	// SampleFailures returns the set of failed nodes, among the given ones, in one trial.
+	SampleFailures(nodes []Node, rng *rand.Rand) NodeSet
+	// Validate returns an error if the model cannot be sampled, e.g. a probability outside [0, 1].
+	Validate() error
+}
+
+// IndependentFailures is a FailureModel where each node fails independently with its own probability.
+type IndependentFailures struct {
+	// Probabilities defines the failure probability of each node, missing nodes never fail.
+	Probabilities FailureProbabilities
+}
+
+// SampleFailures returns the set of failed nodes in one trial.
+func (m IndependentFailures) SampleFailures(nodes []Node, rng *rand.Rand) NodeSet {
+	failed := make(NodeSet)
+
+	for _, n := range nodes {
+		if rng.Float64() < m.Probabilities[n] {
+			failed[n] = true
+		}
+	}
+
+	return failed
+}
+
+// Validate returns an error if a failure probability is outside [0, 1].
+func (m IndependentFailures) Validate() error {
+	for n, p := range m.Probabilities {
+		if !(p >= 0 && p <= 1) {
+			return fmt.Errorf("failure probability of node %s must be in [0, 1]", n.Name)
+		}
+	}
+
+	return nil
+}
+
+// DomainFailures is a FailureModel where whole failure domains fail together, e.g. a rack losing power takes down
+// all of its nodes. On top of that, each node can also fail independently.
+type DomainFailures struct {
+	// Level defines the failure domain level (e.g. "rack") of the correlated failures.
+	Level string
+	// Probabilities defines the failure probability of each domain at Level, missing domains never fail.
+	Probabilities map[string]Probability
+	// NodeProbabilities defines the independent failure probability of each node, missing nodes never fail.
+	NodeProbabilities FailureProbabilities
+}
+
+// SampleFailures returns the set of failed nodes in one trial.
+func (m DomainFailures) SampleFailures(nodes []Node, rng *rand.Rand) NodeSet {
+	domains := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, n := range nodes {
+		if domain, ok := n.Domain(m.Level); ok && !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+
+	// Sample the domains in a fixed order, so that a seeded simulation is reproducible.
+	sort.Strings(domains)
+
+	failedDomains := make(map[string]bool)
+
+	for _, domain := range domains {
+		if rng.Float64() < m.Probabilities[domain] {
+			failedDomains[domain] = true
+		}
+	}
+
+	failed := IndependentFailures{Probabilities: m.NodeProbabilities}.SampleFailures(nodes, rng)
+
+	for _, n := range nodes {
+		if domain, ok := n.Domain(m.Level); ok && failedDomains[domain] {
+			failed[n] = true
+		}
+	}
+
+	return failed
+}
+
+// Validate returns an error if a failure probability of a domain or of a node is outside [0, 1].
+func (m DomainFailures) Validate() error {
+	for domain, p := range m.Probabilities {
+		if !(p >= 0 && p <= 1) {
+			return fmt.Errorf("failure probability of domain %s must be in [0, 1]", domain)
+		}
+	}
+
+	return IndependentFailures{Probabilities: m.NodeProbabilities}.Validate()
+}
+
+// FailureScenario describes a set of nodes failing together and the weight of the scenario.
+type FailureScenario struct {
+	Failed NodeSet
+	Weight Weight
+}
+
+// ScenarioFailures is a FailureModel sampling one of user-provided scenarios in each trial, proportionally to
+// their weights.
+type ScenarioFailures struct {
+	Scenarios []FailureScenario
+}
+
+// SampleFailures returns the set of failed nodes in one trial.
+func (m ScenarioFailures) SampleFailures(nodes []Node, rng *rand.Rand) NodeSet {
+	totalWeight := 0.0
+
+	for _, s := range m.Scenarios {
+		totalWeight += s.Weight
+	}
+
+	pick := rng.Float64() * totalWeight
+
+	for _, s := range m.Scenarios {
+		if pick < s.Weight {
+			return s.Failed
+		}
+		pick -= s.Weight
+	}
+
+	return NodeSet{}
+}
+
+// Validate returns an error if there are no scenarios or a weight is not > 0.
+func (m ScenarioFailures) Validate() error {
+	if len(m.Scenarios) == 0 {
+		return fmt.Errorf("at least one failure scenario must be given")
+	}
+
+	for i, s := range m.Scenarios {
+		if !(s.Weight > 0) || math.IsInf(s.Weight, 1) {
+			return fmt.Errorf("the weight of failure scenario %d must be > 0 and finite", i)
+		}
+	}
+
+	return nil
+}
+
+// SimulationOptions describes the options of a Monte Carlo failure simulation.
+type SimulationOptions struct {
+	// Model defines how the node failures are sampled.
+	Model FailureModel
+	// Trials defines the number of sampled trials.
+	Trials uint
+	// Seed defines the seed of the random number generator, the same seed gives the same results.
+	Seed int64
+	// Confidence defines the level of the confidence intervals, 0.95 by default.
+	Confidence float64
+	// Strategy optionally defines the strategy whose quorum failures are estimated.
+	Strategy *Strategy
+}
+
+// Estimate describes a Monte Carlo estimate and its confidence interval.
+type Estimate struct {
+	Mean  float64
+	Lower float64
+	Upper float64
+}
+
+// SimulationResult describes the result of a Monte Carlo failure simulation.
+type SimulationResult struct {
+	// ReadAvailability is the probability that at least one read quorum is fully alive.
+	ReadAvailability Estimate
+	// WriteAvailability is the probability that at least one write quorum is fully alive.
+	WriteAvailability Estimate
+	// ReadQuorumFailures is the expected fraction of the read quorums selected by the strategy that fail.
+	ReadQuorumFailures Estimate
+	// WriteQuorumFailures is the expected fraction of the write quorums selected by the strategy that fail.
+	WriteQuorumFailures Estimate
+}
+
+// Simulate estimates the availability of the quorum system, and optionally the failures of the quorums selected by
+// a strategy, with a Monte Carlo simulation of the node failures.
+func (qs QuorumSystem) Simulate(options SimulationOptions) (SimulationResult, error) {
+	if options.Model == nil {
+		return SimulationResult{}, fmt.Errorf("a failure model must be set")
+	}
+
+	if options.Trials == 0 {
+		return SimulationResult{}, fmt.Errorf("trials must be > 0")
+	}
+
+	if err := options.Model.Validate(); err != nil {
+		return SimulationResult{}, err
+	}
+
+	confidence := options.Confidence
+
+	if confidence == 0 {
+		confidence = 0.95
+	}
+
+	if confidence <= 0 || confidence >= 1 {
+		return SimulationResult{}, fmt.Errorf("confidence must be in (0, 1)")
+	}
+
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	rng := rand.New(rand.NewSource(options.Seed))
+
+	// Sort the nodes, so that a seeded simulation is reproducible.
+	nodes := qs.GetNodesAsArray()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	readAlive, writeAlive := 0.0, 0.0
+	readFailures, writeFailures := make([]float64, 0), make([]float64, 0)
+
+	for i := uint(0); i < options.Trials; i++ {
+		failed := options.Model.SampleFailures(nodes, rng)
+		alive := make(ExprSet)
+
+		for _, n := range nodes {
+			if !failed[n] {
+				alive[n] = true
+			}
+		}
+
+		if qs.IsReadQuorum(alive) {
+			readAlive++
+		}
+
+		if qs.IsWriteQuorum(alive) {
+			writeAlive++
+		}
+
+		if options.Strategy != nil {
+			readFailures = append(readFailures, failedFraction(options.Strategy.SigmaR, failed))
+			writeFailures = append(writeFailures, failedFraction(options.Strategy.SigmaW, failed))
+		}
+	}
+
+	result := SimulationResult{
+		ReadAvailability:  proportionEstimate(readAlive, float64(options.Trials), z),
+		WriteAvailability: proportionEstimate(writeAlive, float64(options.Trials), z),
+	}
+
+	if options.Strategy != nil {
+		result.ReadQuorumFailures = meanEstimate(readFailures, z)
+		result.WriteQuorumFailures = meanEstimate(writeFailures, z)
+	}
+
+	return result, nil
+}
+
+// failedFraction returns the probability of selecting a quorum with at least one failed node.
+func failedFraction(sigma Sigma, failed NodeSet) float64 {
+	total := 0.0
+
+	for _, s := range sigma.Values {
+		for x := range s.Quorum {
+			if failed[x.(Node)] {
+				total += s.Probability
+				break
+			}
+		}
+	}
+
+	return total
+}
+
+// proportionEstimate returns the Wilson score interval of a proportion, which stays meaningful for proportions
+// close to 0 or 1.
+func proportionEstimate(successes float64, trials float64, z float64) Estimate {
+	p := successes / trials
+	denominator := 1 + z*z/trials
+	center := (p + z*z/(2*trials)) / denominator
+	margin := z / denominator * math.Sqrt(p*(1-p)/trials+z*z/(4*trials*trials))
+
+	return Estimate{Mean: p, Lower: math.Max(0, center-margin), Upper: math.Min(1, center+margin)}
+}
+
+// meanEstimate returns the normal confidence interval of the mean of the samples.
+func meanEstimate(samples []float64, z float64) Estimate {
+	n := float64(len(samples))
+	mean := 0.0
+
+	for _, s := range samples {
+		mean += s
+	}
+	mean /= n
+
+	variance := 0.0
+
+	for _, s := range samples {
+		variance += (s - mean) * (s - mean)
+	}
+
+	if n > 1 {
+		variance /= n - 1
+	}
+
+	margin := z * math.Sqrt(variance/n)
+
+	return Estimate{Mean: mean, Lower: math.Max(0, mean-margin), Upper: math.Min(1, mean+margin)}
+}
@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	a, b, c := NewNode("a").WithDomains(FailureDomains{"rack": "r1"}),
+		NewNode("b").WithDomains(FailureDomains{"rack": "r1"}),
+		NewNode("c").WithDomains(FailureDomains{"rack": "r2"})
+
+	majority, _ := NewChoose(2, []Expr{a, b, c})
+	qs := NewQuorumSystemWithReads(majority)
+
+	// Independent failures: the estimate matches the exact availability.
+	fp := FailureProbabilities{a: 0.1, b: 0.2, c: 0.3}
+	availability, _ := qs.ReadAvailability(fp)
+	exact := availability.Probability
+
+	options := SimulationOptions{Model: IndependentFailures{Probabilities: fp}, Trials: 20000, Seed: 42}
+	result, err := qs.Simulate(options)
+	assert.NilError(t, err)
+	assert.Assert(t, result.ReadAvailability.Lower <= exact && exact <= result.ReadAvailability.Upper,
+		fmt.Sprintf("Exact: %f | Estimate: %v", exact, result.ReadAvailability))
+
+	// The same seed gives the same results.
+	again, _ := qs.Simulate(options)
+	assert.DeepEqual(t, result, again)
+
+	// Correlated failures: r1 going down takes out a and b, and the majority with them.
+	result, err = qs.Simulate(SimulationOptions{
+		Model:  DomainFailures{Level: "rack", Probabilities: map[string]Probability{"r1": 0.2}},
+		Trials: 20000,
+		Seed:   42,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, result.ReadAvailability.Lower <= 0.8 && 0.8 <= result.ReadAvailability.Upper,
+		fmt.Sprintf("Estimate: %v", result.ReadAvailability))
+
+	// User provided scenarios.
+	strategy, _ := NewQuorumSystemWithReads(a.Add(b).Add(c)).UniformStrategy(0)
+
+	result, err = NewQuorumSystemWithReads(a.Add(b).Add(c)).Simulate(SimulationOptions{
+		Model: ScenarioFailures{Scenarios: []FailureScenario{
+			{Failed: NodeSet{a: true}, Weight: 1},
+		}},
+		Trials:   100,
+		Strategy: &strategy,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, result.ReadAvailability.Mean, 1.0)
+	assert.Equal(t, result.WriteAvailability.Mean, 0.0)
+	assert.Assert(t, math.Abs(result.ReadQuorumFailures.Mean-1.0/3) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(result.ReadQuorumFailures.Upper-result.ReadQuorumFailures.Lower) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(result.WriteQuorumFailures.Mean-1) <= float64EqualityThreshold)
+
+	_, err = qs.Simulate(SimulationOptions{Trials: 10})
+	assert.Error(t, err, "a failure model must be set")
+
+	_, err = qs.Simulate(SimulationOptions{Model: IndependentFailures{}})
+	assert.Error(t, err, "trials must be > 0")
+
+	_, err = qs.Simulate(SimulationOptions{Model: IndependentFailures{}, Trials: 10, Confidence: 1})
+	assert.Error(t, err, "confidence must be in (0, 1)")
+
+	_, err = qs.Simulate(SimulationOptions{Model: IndependentFailures{Probabilities: FailureProbabilities{a: 1.5}}, Trials: 10})
+	assert.Error(t, err, "failure probability of node a must be in [0, 1]")
+
+	_, err = qs.Simulate(SimulationOptions{
+		Model:  DomainFailures{Level: "rack", Probabilities: map[string]Probability{"r1": -0.1}},
+		Trials: 10,
+	})
+	assert.Error(t, err, "failure probability of domain r1 must be in [0, 1]")
+
+	_, err = qs.Simulate(SimulationOptions{
+		Model:  DomainFailures{Level: "rack", NodeProbabilities: FailureProbabilities{c: math.NaN()}},
+		Trials: 10,
+	})
+	assert.Error(t, err, "failure probability of node c must be in [0, 1]")
+
+	_, err = qs.Simulate(SimulationOptions{Model: ScenarioFailures{}, Trials: 10})
+	assert.Error(t, err, "at least one failure scenario must be given")
+
+	_, err = qs.Simulate(SimulationOptions{
+		Model:  ScenarioFailures{Scenarios: []FailureScenario{{Failed: NodeSet{a: true}, Weight: 1}, {Weight: 0}}},
+		Trials: 10,
+	})
+	assert.Error(t, err, "the weight of failure scenario 1 must be > 0 and finite")
+}
@@ -0,0 +1,64 @@
+package pkg
+
+import "errors"
+
+// The errors returned by a Solver, possibly wrapped, when the LinearProgram has no optimal solution.
+var (
+	// ErrInfeasible is returned when no solution satisfies the bounds and the rows.
+	ErrInfeasible = errors.New("the linear program is infeasible")
+	// ErrUnbounded is returned when the objective can decrease without limit.
+	ErrUnbounded = errors.New("the linear program is unbounded")
+	// ErrIterationLimit is returned when the solver stops before reaching the optimum.
+	ErrIterationLimit = errors.New("the solver reached its iteration limit")
+)
+
+// LinearProgram describes a linear program in the form used by the quorum system:
+//
+//	minimize   Σ Objective[j] * x_j
+//	subject to Bounds[j][0] ≤ x_j ≤ Bounds[j][1]
+//	           row[0] ≤ Σ row[1+j] * x_j ≤ row[len(row)-1], for each row of Rows
+//
+// The bounds of the variables and the rows may be infinite.
+type LinearProgram struct {
+	Objective []float64
+	Bounds    [][2]float64
+	Rows      [][]float64
+}
+
+// LinearProgramSolution describes the optimal solution of a LinearProgram.
+type LinearProgramSolution struct {
+	// Primal is the value of each variable.
+	Primal []float64
+	// RowDuals is the dual value of each row, i.e. the change of the objective per unit of change of its bound.
+	RowDuals []float64
+	// ReducedCosts is the reduced cost of each variable.
+	ReducedCosts []float64
+	// Objective is the optimal value of the objective.
+	Objective float64
+}
+
+// Solver solves a LinearProgram. When the program has no optimal solution, the error returned wraps ErrInfeasible,
+// ErrUnbounded or ErrIterationLimit, so that they can be told apart with errors.Is. Any other error means that the
+// program is malformed or that the solver failed.
+type Solver interface {
+	Solve(lp LinearProgram) (LinearProgramSolution, error)
+}
+
+// solver is the Solver used to compute the strategies.
+var solver = defaultSolver
+
+// SetSolver sets the Solver used to compute the strategies, a nil Solver restores the default one: CLPSolver when
+// the package is built with cgo and the clp tag, SimplexSolver otherwise. It must not be called while strategies are
+// computed.
+func SetSolver(s Solver) {
+	if s == nil {
+		s = defaultSolver
+	}
+
+	solver = s
+}
+
+// GetSolver returns the Solver used to compute the strategies.
+func GetSolver() Solver {
+	return solver
+}
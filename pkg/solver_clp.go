@@ -0,0 +1,48 @@
+//go:build cgo && clp
+// +build cgo,clp
+
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/lanl/clp"
+)
+
+// defaultSolver is CLP when the package is built with cgo and the clp tag.
+var defaultSolver Solver = CLPSolver{}
+
+// clpStopped is the CLP status of a simplex stopped on the iteration or time limit.
+const clpStopped clp.SimplexStatus = 3
+
+// CLPSolver is the Solver backed by the COIN-OR CLP library through github.com/lanl/clp. It requires cgo, the CLP
+// library to be installed, and the clp build tag, e.g. go build -tags clp.
+type CLPSolver struct{}
+
+// Solve minimizes the LinearProgram with the CLP primal simplex.
+func (CLPSolver) Solve(lp LinearProgram) (LinearProgramSolution, error) {
+	simp := clp.NewSimplex()
+	simp.SetOptimizationDirection(clp.Minimize)
+	simp.EasyLoadDenseProblem(lp.Objective, lp.Bounds, lp.Rows)
+
+	status := simp.Primal(clp.NoValuesPass, clp.NoStartFinishOptions)
+
+	switch status {
+	case clp.Optimal:
+	case clp.Infeasible:
+		return LinearProgramSolution{}, ErrInfeasible
+	case clp.Unbounded:
+		return LinearProgramSolution{}, ErrUnbounded
+	case clpStopped:
+		return LinearProgramSolution{}, fmt.Errorf("%w: clp stopped on iterations or time", ErrIterationLimit)
+	default:
+		return LinearProgramSolution{}, fmt.Errorf("clp status %d", status)
+	}
+
+	return LinearProgramSolution{
+		Primal:       simp.PrimalColumnSolution(),
+		RowDuals:     simp.DualRowSolution(),
+		ReducedCosts: simp.DualColumnSolution(),
+		Objective:    simp.ObjectiveValue(),
+	}, nil
+}
@@ -0,0 +1,56 @@
+//go:build cgo && clp
+// +build cgo,clp
+
+package pkg
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCLPSolverCrossCheck(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	defer SetSolver(nil)
+
+	a := NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Second)
+	b := NewNodeWithCapacityAndLatency("b", 1, 2, 2*time.Second)
+	c := NewNodeWithCapacityAndLatency("c", 1, 1, 3*time.Second)
+	d := NewNodeWithCapacityAndLatency("d", 3, 1, 4*time.Second)
+	grid := NewQuorumSystemWithReads(a.Multiply(b).Add(c.Multiply(d)))
+
+	majority, err := NewChoose(2, []Expr{a, b, c})
+	assert.NilError(t, err)
+
+	rf := QuorumDistribution{values: DistributionValues{0.1: 1, 0.5: 1, 0.9: 2}}
+	loadLimit := 0.9
+
+	for _, qs := range []QuorumSystem{grid, NewQuorumSystemWithReads(majority)} {
+		for _, optimize := range []OptimizeType{Load, Network, Latency} {
+			options := StrategyOptions{Optimize: optimize, ReadFraction: rf}
+
+			if optimize != Load {
+				options.LoadLimit = &loadLimit
+			}
+
+			metric := func(s Solver) float64 {
+				SetSolver(s)
+
+				strategy, err := qs.Strategy(initializeStrategyOptions(options))
+				assert.NilError(t, err)
+
+				var fr Distribution = rf
+				var wf Distribution
+
+				value, err := strategy.metric(optimize, defaultPercentile, &fr, &wf)
+				assert.NilError(t, err)
+
+				return value
+			}
+
+			assert.Assert(t, math.Abs(metric(CLPSolver{})-metric(SimplexSolver{})) <= float64EqualityThreshold)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !cgo || !clp
+// +build !cgo !clp
+
+package pkg
+
+// defaultSolver is the pure-Go simplex unless the package is built with cgo and the clp tag.
+var defaultSolver Solver = SimplexSolver{}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestSetSolver(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	defer SetSolver(nil)
+
+	a, b, c := NewNodeWithCapacity("a", 2, 1), NewNodeWithCapacity("b", 1, 2), NewNodeWithCapacity("c", 1, 1)
+	majority, err := NewChoose(2, []Expr{a, b, c})
+	assert.NilError(t, err)
+
+	qs := NewQuorumSystemWithReads(majority)
+
+	var rf Distribution = QuorumDistribution{values: DistributionValues{0.25: 1, 0.75: 2}}
+	var wf Distribution
+
+	metrics := func() (float64, float64) {
+		strategy, err := qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+		assert.NilError(t, err)
+
+		load, err := strategy.Load(&rf, &wf)
+		assert.NilError(t, err)
+
+		latency, err := strategy.Latency(&rf, &wf)
+		assert.NilError(t, err)
+
+		return load, latency
+	}
+
+	defaultLoad, _ := metrics()
+
+	// The strategies of the pure-Go simplex have the same load as those of the default Solver.
+	SetSolver(SimplexSolver{})
+	assert.Equal(t, GetSolver(), Solver(SimplexSolver{}))
+
+	load, _ := metrics()
+	assert.Assert(t, math.Abs(load-defaultLoad) <= float64EqualityThreshold)
+
+	SetSolver(nil)
+	assert.Equal(t, GetSolver(), defaultSolver)
+
+	// The infeasible and unbounded programs are reported as the lack of an optimal strategy.
+	SetSolver(failingSolver{err: fmt.Errorf("%w: failing solver", ErrInfeasible)})
+
+	_, err = qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.Error(t, err, "no optimal strategy found: the linear program is infeasible: failing solver")
+	assert.Assert(t, errors.Is(err, errNoOptimalStrategy))
+
+	SetSolver(failingSolver{err: ErrUnbounded})
+
+	_, err = qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.Assert(t, errors.Is(err, errNoOptimalStrategy))
+
+	// The other errors of the Solver are returned as they are.
+	SetSolver(failingSolver{err: fmt.Errorf("%w of 10 pivots", ErrIterationLimit)})
+
+	_, err = qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.Error(t, err, "the solver reached its iteration limit of 10 pivots")
+	assert.Assert(t, !errors.Is(err, errNoOptimalStrategy))
+
+	SetSolver(failingSolver{err: fmt.Errorf("failing solver")})
+
+	_, err = qs.Strategy(initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: rf}))
+	assert.Error(t, err, "failing solver")
+}
+
+// failingSolver is a Solver that always fails with err.
+type failingSolver struct {
+	err error
+}
+
+func (s failingSolver) Solve(LinearProgram) (LinearProgramSolution, error) {
+	return LinearProgramSolution{}, s.err
+}
@@ -1,11 +1,19 @@
 package pkg
 
 import (
+	"fmt"
 	wr "github.com/mroth/weightedrand"
+	"math"
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 )
 
+// probabilityThreshold is the probability under which a quorum is considered never chosen, it hides the rounding
+// errors of the LP solver.
+const probabilityThreshold = 1e-9
+
 // OptimizeType describes an optimization type
 type OptimizeType string
 
@@ -13,8 +21,23 @@ const (
 	Load    OptimizeType = "Load"
 	Network OptimizeType = "Network"
 	Latency OptimizeType = "Latency"
+	// Cost optimizes the monetary cost of an operation, given the cost per read and per write of the nodes.
+	Cost OptimizeType = "Cost"
+	// Weighted optimizes the weighted sum of the load, the network load, the latency and the cost defined by Weights.
+	Weighted OptimizeType = "Weighted"
+	// TailLatency optimizes the latency percentile defined by Percentile, given the LatencyDistribution of the nodes.
+	TailLatency OptimizeType = "TailLatency"
 )
 
+// ObjectiveWeights describes the weights of the metrics in the Weighted optimization. Each metric is normalized by its
+// optimal value, e.g. a load twice the optimal load counts as 2, so that the weights do not depend on the units.
+type ObjectiveWeights struct {
+	Load    float64
+	Network float64
+	Latency float64
+	Cost    float64
+}
+
 // StrategyOptions describes the quorum system strategy options.
 type StrategyOptions struct {
 	// Optimize defines the target optimization.
@@ -23,8 +46,14 @@ type StrategyOptions struct {
 	LoadLimit *float64
 	// NetworkLimit defines the limit on the network limit.
 	NetworkLimit *float64
-	// LatencyLimit defines the limit on the latency.
+	// LatencyLimit defines the limit on the latency, in milliseconds.
 	LatencyLimit *float64
+	// CostLimit defines the limit on the cost of an operation.
+	CostLimit *float64
+	// Weights defines the weights of the metrics when optimizing for Weighted.
+	Weights ObjectiveWeights
+	// Percentile defines the latency percentile (e.g. 0.99, the default) when optimizing for TailLatency.
+	Percentile float64
 	// ReadFraction defines the workflow distribution for the read operations.
 	ReadFraction Distribution
 	// WriteFraction defines the workflow distribution for the write operations.
@@ -32,6 +61,13 @@ type StrategyOptions struct {
 	// F r ∈ R is F-resilient for some integer f if despite removing
 	// any f nodes from r, r is still a read quorum
 	F uint
+	// DomainLevel defines the failure domain level (e.g. "zone") used by D and MinDomains.
+	DomainLevel string
+	// D r ∈ R is D-domain-resilient for some integer d if despite removing
+	// the nodes of any d failure domains from r, r is still a read quorum
+	D uint
+	// MinDomains restricts the strategy to the quorums spanning at least MinDomains failure domains.
+	MinDomains uint
 }
 
 // Strategy defines a strategy related to a QuorumSystem.
@@ -155,7 +191,8 @@ func (s Strategy) Capacity(rf *Distribution, wf *Distribution) (float64, error)
 	return sum, nil
 }
 
-// NetworkLoad calculates and returns the network load of the strategy given a read and write Distribution.
+// NetworkLoad calculates and returns the network load of the strategy given a read and write Distribution. Each node
+// of a quorum counts as its link cost times the payload size of the operation, i.e. as 1 by default.
 func (s Strategy) NetworkLoad(rf *Distribution, wf *Distribution) (float64, error) {
 	d, err := canonicalizeReadsWrites(rf, wf)
 	if err != nil {
@@ -170,20 +207,25 @@ func (s Strategy) NetworkLoad(rf *Distribution, wf *Distribution) (float64, erro
 
 	reads := 0.0
 	for _, sigma := range s.SigmaR.Values {
-		reads += frsum * float64(len(sigma.Quorum)) * sigma.Probability
+		reads += frsum * s.Qs.readQuorumNetworkLoad(sigma.Quorum) * sigma.Probability
 	}
 
 	writes := 0.0
 	for _, sigma := range s.SigmaW.Values {
-		writes += (1 - frsum) * float64(len(sigma.Quorum)) * sigma.Probability
+		writes += (1 - frsum) * s.Qs.writeQuorumNetworkLoad(sigma.Quorum) * sigma.Probability
 	}
 
 	total := reads + writes
 	return total, nil
 }
 
-// Latency calculates and returns the latency of the strategy given a read and write Distribution.
+// Latency calculates and returns the latency of the strategy given a read and write Distribution, in milliseconds.
 func (s Strategy) Latency(rf *Distribution, wf *Distribution) (float64, error) {
+	return s.latency(rf, wf, Node.GetLatency)
+}
+
+// latency returns the latency of the strategy given a read and write Distribution and the latency of each node.
+func (s Strategy) latency(rf *Distribution, wf *Distribution, latency func(n Node) time.Duration) (float64, error) {
 	d, err := canonicalizeReadsWrites(rf, wf)
 	if err != nil {
 		return -1, err
@@ -204,13 +246,13 @@ func (s Strategy) Latency(rf *Distribution, wf *Distribution) (float64, error) {
 			nodes = append(nodes, s.Qs.GetNodeByName(n.String()))
 		}
 
-		v, err := s.Qs.readQuorumLatency(nodes)
+		v, err := s.Qs.readQuorumLatency(nodes, latency)
 
 		if err != nil {
 			return -1, err
 		}
 
-		reads += float64(v) * rq.Probability
+		reads += milliseconds(v) * rq.Probability
 	}
 
 	writes := 0.0
@@ -222,12 +264,12 @@ func (s Strategy) Latency(rf *Distribution, wf *Distribution) (float64, error) {
 			nodes = append(nodes, s.Qs.GetNodeByName(n.String()))
 		}
 
-		v, err := s.Qs.writeQuorumLatency(nodes)
+		v, err := s.Qs.writeQuorumLatency(nodes, latency)
 
 		if err != nil {
 			return -1, err
 		}
-		writes += float64(v) * wq.Probability
+		writes += milliseconds(v) * wq.Probability
 	}
 
 	total := frsum*reads + (1-frsum)*writes
@@ -279,8 +321,36 @@ func (s Strategy) NodeThroughput(node Node, rf *Distribution, wf *Distribution)
 	return &sum, nil
 }
 
+// String returns the read and write quorums chosen with a non-zero probability, e.g.
+// Strategy(reads={{a, b}: 0.5, {c, d}: 0.5}, writes={{a, c}: 1}).
 func (s Strategy) String() string {
-	return "TODO"
+	return fmt.Sprintf("Strategy(reads=%s, writes=%s)", s.SigmaR, s.SigmaW)
+}
+
+// String returns the quorums chosen with a non-zero probability and their probabilities.
+func (sigma Sigma) String() string {
+	records := make([]string, 0, len(sigma.Values))
+
+	for _, r := range sigma.Values {
+		if r.Probability > probabilityThreshold {
+			records = append(records, fmt.Sprintf("%s: %.4g", formatQuorum(r.Quorum), r.Probability))
+		}
+	}
+
+	return "{" + strings.Join(records, ", ") + "}"
+}
+
+// formatQuorum returns the sorted names of the nodes of a quorum, e.g. {a, b}.
+func formatQuorum(quorum ExprSet) string {
+	names := make([]string, 0, len(quorum))
+
+	for x := range quorum {
+		names = append(names, x.String())
+	}
+
+	sort.Strings(names)
+
+	return "{" + strings.Join(names, ", ") + "}"
 }
 
 // getMaxLoad returns the max load of the strategy for a specific fraction.
@@ -298,20 +368,61 @@ func (s Strategy) getMaxLoad(fr float64) float64 {
 
 // getNodeLoad returns the load of a node for a given probability.
 func (s Strategy) getNodeLoad(node Node, fr float64) float64 {
+	reads, writes := s.getNodeReadWriteLoad(node, fr)
+	return reads + writes
+}
+
+// getNodeReadWriteLoad returns the load of a node due to the reads and due to the writes for a given read fraction.
+func (s Strategy) getNodeReadWriteLoad(node Node, fr float64) (float64, float64) {
 	fw := 1 - fr
-	return fr*s.nodeToReadProbability[node]/float64(*node.ReadCapacity) +
-		fw*s.nodeToWriteProbability[node]/float64(*node.WriteCapacity)
+	return fr * s.nodeToReadProbability[node] / node.GetReadCapacity(),
+		fw * s.nodeToWriteProbability[node] / node.GetWriteCapacity()
 }
 
 func (s Strategy) nodeUtilization(node Node, fr float64) float64 {
-	return s.getNodeLoad(node, fr) / s.getMaxLoad(fr)
+	reads, writes := s.getNodeReadWriteUtilization(node, fr)
+	return reads + writes
+}
+
+// getNodeReadWriteUtilization returns the utilization of a node due to the reads and due to the writes for a given
+// read fraction, i.e. its load relative to the max load. A strategy that loads no node has a utilization of 0.
+func (s Strategy) getNodeReadWriteUtilization(node Node, fr float64) (float64, float64) {
+	maxLoad := s.getMaxLoad(fr)
+
+	if maxLoad == 0 {
+		return 0, 0
+	}
+
+	reads, writes := s.getNodeReadWriteLoad(node, fr)
+
+	return reads / maxLoad, writes / maxLoad
 }
 
 func (s Strategy) nodeThroughput(node Node, fr float64) float64 {
-	capacity := 1 / s.getMaxLoad(fr)
-	fw := 1 - fr
+	reads, writes := s.getNodeReadWriteThroughput(node, fr)
+	return reads + writes
+}
+
+// getNodeReadWriteThroughput returns the reads and the writes processed by a node at the capacity of the strategy
+// for a given read fraction. A strategy that loads no node has an infinite capacity: the nodes it uses have an
+// infinite throughput, the others a throughput of 0.
+func (s Strategy) getNodeReadWriteThroughput(node Node, fr float64) (float64, float64) {
+	maxLoad := s.getMaxLoad(fr)
+	reads, writes := fr*s.nodeToReadProbability[node], (1-fr)*s.nodeToWriteProbability[node]
+
+	if maxLoad > 0 {
+		return reads / maxLoad, writes / maxLoad
+	}
+
+	if reads > 0 {
+		reads = math.Inf(1)
+	}
+
+	if writes > 0 {
+		writes = math.Inf(1)
+	}
 
-	return capacity * (fr*s.nodeToReadProbability[node] + fw*s.nodeToWriteProbability[node])
+	return reads, writes
 }
 
 func initializeStrategyOptions(initOptions StrategyOptions) func(options *StrategyOptions) error {
@@ -320,7 +431,13 @@ func initializeStrategyOptions(initOptions StrategyOptions) func(options *Strate
 		options.LatencyLimit = initOptions.LatencyLimit
 		options.NetworkLimit = initOptions.NetworkLimit
 		options.LoadLimit = initOptions.LoadLimit
+		options.CostLimit = initOptions.CostLimit
+		options.Weights = initOptions.Weights
+		options.Percentile = initOptions.Percentile
 		options.F = initOptions.F
+		options.DomainLevel = initOptions.DomainLevel
+		options.D = initOptions.D
+		options.MinDomains = initOptions.MinDomains
 		options.ReadFraction = initOptions.ReadFraction
 		options.WriteFraction = initOptions.WriteFraction
 
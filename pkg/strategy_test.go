@@ -5,6 +5,7 @@ import (
 	"gotest.tools/assert"
 	"math"
 	"testing"
+	"time"
 )
 
 func TestGetQuorum(t *testing.T) {
@@ -63,7 +64,7 @@ func TestNetworkLoad(t *testing.T) {
 func TestLatency(t *testing.T) {
 	const float64EqualityThreshold = 1e-9
 
-	a, b, c, d, e := NewNodeWithLatency("a", 1), NewNodeWithLatency("b", 2), NewNodeWithLatency("c", 3), NewNodeWithLatency("d", 4), NewNodeWithLatency("e", 5)
+	a, b, c, d, e := NewNodeWithLatency("a", 1*time.Millisecond), NewNodeWithLatency("b", 2*time.Millisecond), NewNodeWithLatency("c", 3*time.Millisecond), NewNodeWithLatency("d", 4*time.Millisecond), NewNodeWithLatency("e", 5*time.Millisecond)
 
 	qs := NewQuorumSystemWithReads(a.Multiply(b).Add(c.Multiply(d).Multiply(e)))
 	sigma, _ := qs.MakeStrategy(
@@ -261,3 +262,21 @@ func TestLoadCapUtil(t *testing.T) {
 	}
 
 }
+
+func TestStrategyString(t *testing.T) {
+	a, b, c, d := NewNode("a"), NewNode("b"), NewNode("c"), NewNode("d")
+	qs := NewQuorumSystemWithReads(a.Multiply(b).Add(c.Multiply(d)))
+
+	sigmaR := Sigma{Values: []SigmaRecord{
+		{Quorum: ExprSet{b: true, a: true}, Probability: 0.75},
+		{Quorum: ExprSet{c: true, d: true}, Probability: 0.25},
+	}}
+	sigmaW := Sigma{Values: []SigmaRecord{
+		{Quorum: ExprSet{a: true, c: true}, Probability: 1},
+		{Quorum: ExprSet{b: true, d: true}, Probability: 0},
+	}}
+
+	strategy := NewStrategy(qs, sigmaR, sigmaW)
+
+	assert.Equal(t, strategy.String(), "Strategy(reads={{a, b}: 0.75, {c, d}: 0.25}, writes={{a, c}: 1})")
+}
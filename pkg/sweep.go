@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Sweep describes the metrics of a QuorumSystem over a grid of read fractions, as series aligned on ReadFractions.
+type Sweep struct {
+	ReadFractions []Fraction
+	Load          []float64
+	Capacity      []float64
+	NetworkLoad   []float64
+	// Latency is in milliseconds.
+	Latency []float64
+	// NodeLoad is the load of each node at each read fraction.
+	NodeLoad map[Node][]float64
+	// Strategies is the strategy evaluated at each read fraction.
+	Strategies []*Strategy
+}
+
+// FractionGrid returns n read fractions evenly spaced between 0 and 1, both included.
+func FractionGrid(n uint) []Fraction {
+	if n == 0 {
+		return []Fraction{}
+	}
+
+	if n == 1 {
+		return []Fraction{0}
+	}
+
+	result := make([]Fraction, 0, n)
+
+	for i := uint(0); i < n; i++ {
+		result = append(result, float64(i)/float64(n-1))
+	}
+
+	return result
+}
+
+// Sweep re-optimizes the strategy at each read fraction and returns its metrics. The ReadFraction and WriteFraction
+// of the StrategyOptions are ignored, the other options (e.g. Optimize and the limits) apply to every point.
+func (qs QuorumSystem) Sweep(fractions []Fraction, strategyOptions StrategyOptions) (Sweep, error) {
+	if err := checkFractions(fractions); err != nil {
+		return Sweep{}, err
+	}
+
+	sweep := newSweep(qs)
+
+	for _, fr := range fractions {
+		options := strategyOptions
+		options.ReadFraction = QuorumDistribution{values: DistributionValues{fr: 1}}
+		options.WriteFraction = nil
+
+		strategy, err := qs.Strategy(initializeStrategyOptions(options))
+
+		if err != nil {
+			return Sweep{}, fmt.Errorf("no strategy for read fraction %v: %s", fr, err)
+		}
+
+		if err := sweep.add(strategy, fr); err != nil {
+			return Sweep{}, err
+		}
+	}
+
+	return sweep, nil
+}
+
+// Sweep evaluates the strategy at each read fraction and returns its metrics. Unlike QuorumSystem.Sweep, the strategy
+// is not re-optimized, which shows whether a single strategy is good enough across the workload.
+func (s Strategy) Sweep(fractions []Fraction) (Sweep, error) {
+	if err := checkFractions(fractions); err != nil {
+		return Sweep{}, err
+	}
+
+	sweep := newSweep(s.Qs)
+
+	for _, fr := range fractions {
+		if err := sweep.add(&s, fr); err != nil {
+			return Sweep{}, err
+		}
+	}
+
+	return sweep, nil
+}
+
+// newSweep returns an empty Sweep over the nodes of the quorum system.
+func newSweep(qs QuorumSystem) Sweep {
+	sweep := Sweep{
+		ReadFractions: make([]Fraction, 0),
+		Load:          make([]float64, 0),
+		Capacity:      make([]float64, 0),
+		NetworkLoad:   make([]float64, 0),
+		Latency:       make([]float64, 0),
+		NodeLoad:      make(map[Node][]float64),
+		Strategies:    make([]*Strategy, 0),
+	}
+
+	for n := range qs.GetNodes() {
+		sweep.NodeLoad[n] = make([]float64, 0)
+	}
+
+	return sweep
+}
+
+// add appends the metrics of the strategy at the read fraction fr to the series.
+func (sw *Sweep) add(s *Strategy, fr Fraction) error {
+	var rf Distribution = QuorumDistribution{values: DistributionValues{fr: 1}}
+	var wf Distribution
+
+	networkLoad, err := s.NetworkLoad(&rf, &wf)
+
+	if err != nil {
+		return err
+	}
+
+	latency, err := s.Latency(&rf, &wf)
+
+	if err != nil {
+		return err
+	}
+
+	maxLoad := s.getMaxLoad(fr)
+
+	sw.ReadFractions = append(sw.ReadFractions, fr)
+	sw.Load = append(sw.Load, maxLoad)
+	sw.Capacity = append(sw.Capacity, 1/maxLoad)
+	sw.NetworkLoad = append(sw.NetworkLoad, networkLoad)
+	sw.Latency = append(sw.Latency, latency)
+	sw.Strategies = append(sw.Strategies, s)
+
+	for n := range sw.NodeLoad {
+		sw.NodeLoad[n] = append(sw.NodeLoad[n], s.getNodeLoad(n, fr))
+	}
+
+	return nil
+}
+
+// Nodes returns the nodes of the sweep sorted by name.
+func (sw Sweep) Nodes() []Node {
+	nodes := make([]Node, 0, len(sw.NodeLoad))
+
+	for n := range sw.NodeLoad {
+		nodes = append(nodes, n)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	return nodes
+}
+
+// checkFractions returns an error if the list of read fractions is empty or a fraction is outside [0, 1].
+func checkFractions(fractions []Fraction) error {
+	if len(fractions) == 0 {
+		return fmt.Errorf("at least one read fraction must be given")
+	}
+
+	for _, fr := range fractions {
+		if fr < 0 || fr > 1 {
+			return fmt.Errorf("read fraction %v must be in [0, 1]", fr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"gotest.tools/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFractionGrid(t *testing.T) {
+	assert.DeepEqual(t, FractionGrid(0), []Fraction{})
+	assert.DeepEqual(t, FractionGrid(1), []Fraction{0})
+	assert.DeepEqual(t, FractionGrid(5), []Fraction{0, 0.25, 0.5, 0.75, 1})
+}
+
+func TestSweep(t *testing.T) {
+	a := NewNodeWithCapacityAndLatency("a", 1, 1, 1*time.Millisecond)
+	b := NewNodeWithCapacityAndLatency("b", 1, 1, 2*time.Millisecond)
+
+	qs := NewQuorumSystemWithReads(a.Add(b))
+
+	sigmaR := Sigma{Values: []SigmaRecord{{Quorum: ExprSet{a: true}, Probability: 0.5}, {Quorum: ExprSet{b: true}, Probability: 0.5}}}
+	sigmaW := Sigma{Values: []SigmaRecord{{Quorum: ExprSet{a: true, b: true}, Probability: 1}}}
+	strategy := NewStrategy(qs, sigmaR, sigmaW)
+
+	sweep, err := strategy.Sweep(FractionGrid(3))
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, sweep.ReadFractions, []Fraction{0, 0.5, 1})
+	assert.DeepEqual(t, sweep.Load, []float64{1, 0.75, 0.5})
+	assert.DeepEqual(t, sweep.Capacity, []float64{1, 1 / 0.75, 2})
+	assert.DeepEqual(t, sweep.NetworkLoad, []float64{2, 1.5, 1})
+	assert.DeepEqual(t, sweep.Latency, []float64{2, 1.75, 1.5})
+	assert.DeepEqual(t, sweep.NodeLoad[a], []float64{1, 0.75, 0.5})
+	assert.DeepEqual(t, sweep.Nodes(), []Node{a, b})
+	assert.Equal(t, len(sweep.Strategies), 3)
+
+	// Re-optimizing for latency moves all the reads to the fastest node.
+	sweep, err = qs.Sweep([]Fraction{0, 1}, StrategyOptions{Optimize: Latency})
+	assert.NilError(t, err)
+
+	assert.Assert(t, math.Abs(sweep.Latency[1]-1) < 1e-6)
+	assert.Assert(t, math.Abs(sweep.NodeLoad[a][1]-1) < 1e-6)
+	assert.Assert(t, math.Abs(sweep.NodeLoad[b][1]) < 1e-6)
+	assert.Assert(t, sweep.Strategies[0] != sweep.Strategies[1])
+
+	// Without latencies, the nodes have the default latency.
+	c, d := NewNodeWithCapacity("c", 1, 1), NewNodeWithCapacity("d", 1, 1)
+	sweep, err = NewQuorumSystemWithReads(c.Add(d)).Sweep([]Fraction{0.5}, StrategyOptions{Optimize: Load})
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(sweep.Latency[0]-1000) < 1e-6)
+	assert.Assert(t, math.Abs(sweep.Load[0]-0.75) < 1e-6)
+
+	_, err = strategy.Sweep([]Fraction{1.5})
+	assert.Error(t, err, "read fraction 1.5 must be in [0, 1]")
+
+	_, err = strategy.Sweep([]Fraction{})
+	assert.Error(t, err, "at least one read fraction must be given")
+}
@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StrategyTable describes one optimal strategy per read fraction of a workload Distribution, so that a client can
+// pick the strategy of the measured read fraction instead of a single strategy averaged over the Distribution.
+type StrategyTable struct {
+	// ReadFractions are the read fractions of the Distribution, sorted, with their probabilities.
+	ReadFractions []Fraction
+	Probabilities []Probability
+	// Strategies is the optimal strategy at each read fraction.
+	Strategies []*Strategy
+	// Averaged is the single strategy optimized over the whole Distribution.
+	Averaged *Strategy
+	// Load is the expected load when each read fraction uses its own strategy.
+	Load float64
+	// AveragedLoad is the expected load of the averaged strategy.
+	AveragedLoad float64
+}
+
+// StrategyTable returns the optimal strategy of each read fraction of the workload Distribution, together with the
+// strategy averaged over the Distribution. Each strategy is computed with QuorumSystem.Strategy and the same options.
+func (qs QuorumSystem) StrategyTable(strategyOptions StrategyOptions) (StrategyTable, error) {
+	d, err := canonicalizeReadsWrites(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+
+	if err != nil {
+		return StrategyTable{}, err
+	}
+
+	averaged, err := qs.Strategy(initializeStrategyOptions(strategyOptions))
+
+	if err != nil {
+		return StrategyTable{}, err
+	}
+
+	averagedLoad, err := averaged.Load(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+
+	if err != nil {
+		return StrategyTable{}, err
+	}
+
+	table := StrategyTable{Averaged: averaged, AveragedLoad: averagedLoad}
+
+	for fr := range d {
+		table.ReadFractions = append(table.ReadFractions, fr)
+	}
+
+	sort.Float64s(table.ReadFractions)
+
+	for _, fr := range table.ReadFractions {
+		options := strategyOptions
+		options.ReadFraction = QuorumDistribution{values: DistributionValues{fr: 1}}
+		options.WriteFraction = nil
+
+		strategy, err := qs.Strategy(initializeStrategyOptions(options))
+
+		if err != nil {
+			return StrategyTable{}, fmt.Errorf("no strategy for read fraction %v: %w", fr, err)
+		}
+
+		table.Probabilities = append(table.Probabilities, d[fr])
+		table.Strategies = append(table.Strategies, strategy)
+		table.Load += d[fr] * strategy.getMaxLoad(fr)
+	}
+
+	return table, nil
+}
+
+// LoadSavings returns how much load the table saves compared with the averaged strategy.
+func (t StrategyTable) LoadSavings() float64 {
+	return t.AveragedLoad - t.Load
+}
+
+// Nearest returns the strategy of the read fraction of the table nearest to the given read fraction. It returns an
+// error when the table has no read fractions.
+func (t StrategyTable) Nearest(fr Fraction) (*Strategy, error) {
+	if err := checkFractions([]Fraction{fr}); err != nil {
+		return nil, err
+	}
+
+	if len(t.ReadFractions) == 0 {
+		return nil, fmt.Errorf("the strategy table has no read fractions")
+	}
+
+	i := sort.SearchFloat64s(t.ReadFractions, fr)
+
+	if i == len(t.ReadFractions) || (i > 0 && fr-t.ReadFractions[i-1] <= t.ReadFractions[i]-fr) {
+		i--
+	}
+
+	return t.Strategies[i], nil
+}
+
+// Interpolate returns the strategy mixing the strategies of the two read fractions of the table around the given
+// read fraction, weighted by their distance to it. Outside the read fractions of the table, it is the strategy of the
+// nearest read fraction. It returns an error when the table has no read fractions.
+func (t StrategyTable) Interpolate(fr Fraction) (*Strategy, error) {
+	if err := checkFractions([]Fraction{fr}); err != nil {
+		return nil, err
+	}
+
+	i := sort.SearchFloat64s(t.ReadFractions, fr)
+
+	if i == 0 || i == len(t.ReadFractions) || t.ReadFractions[i] == fr {
+		return t.Nearest(fr)
+	}
+
+	lower, upper := t.ReadFractions[i-1], t.ReadFractions[i]
+	w := (fr - lower) / (upper - lower)
+
+	strategy := mixStrategies(t.Strategies[i-1], t.Strategies[i], w)
+
+	return &strategy, nil
+}
+
+// mixStrategies returns the strategy choosing the quorums of s with probability 1 - w and those of o with
+// probability w.
+func mixStrategies(s *Strategy, o *Strategy, w float64) Strategy {
+	mix := func(lhs Sigma, rhs Sigma) Sigma {
+		quorums := make(map[string]ExprSet)
+		probabilities := make(map[string]Probability)
+		keys := make([]string, 0)
+
+		add := func(sigma Sigma, weight float64) {
+			for _, r := range sigma.Values {
+				key := setKey(r.Quorum)
+
+				if _, ok := quorums[key]; !ok {
+					quorums[key] = r.Quorum
+					keys = append(keys, key)
+				}
+
+				probabilities[key] += weight * r.Probability
+			}
+		}
+
+		add(lhs, 1-w)
+		add(rhs, w)
+
+		result := Sigma{Values: make([]SigmaRecord, 0, len(keys))}
+
+		for _, key := range keys {
+			result.Values = append(result.Values, SigmaRecord{Quorum: quorums[key], Probability: probabilities[key]})
+		}
+
+		return result
+	}
+
+	return NewStrategy(s.Qs, mix(s.SigmaR, o.SigmaR), mix(s.SigmaW, o.SigmaW))
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"testing"
+)
+
+func TestStrategyTable(t *testing.T) {
+	const float64EqualityThreshold = 1e-6
+
+	a, b, c := NewNodeWithCapacity("a", 4, 1), NewNodeWithCapacity("b", 1, 4), NewNodeWithCapacity("c", 2, 2)
+	majority, err := NewChoose(2, []Expr{a, b, c})
+	assert.NilError(t, err)
+
+	qs := NewQuorumSystemWithReads(majority)
+
+	strategyOptions := StrategyOptions{
+		Optimize: Load,
+		ReadFraction: QuorumDistribution{
+			values: map[Fraction]Weight{0.1: 1, 0.9: 1}},
+	}
+
+	table, err := qs.StrategyTable(strategyOptions)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, table.ReadFractions, []Fraction{0.1, 0.9})
+	assert.DeepEqual(t, table.Probabilities, []Probability{0.5, 0.5})
+
+	// Each read fraction uses the nodes with the matching capacities, which the averaged strategy cannot do.
+	expected := 0.0
+
+	for i, fr := range table.ReadFractions {
+		expected += table.Probabilities[i] * table.Strategies[i].getMaxLoad(fr)
+	}
+
+	assert.Assert(t, math.Abs(table.Load-expected) < float64EqualityThreshold)
+	assert.Assert(t, table.LoadSavings() > float64EqualityThreshold, fmt.Sprintf("Actual:%f", table.LoadSavings()))
+	assert.Assert(t, math.Abs(table.AveragedLoad-table.Load-table.LoadSavings()) < float64EqualityThreshold)
+
+	s, err := table.Nearest(0.3)
+	assert.NilError(t, err)
+	assert.Equal(t, s, table.Strategies[0])
+
+	s, err = table.Nearest(0.6)
+	assert.NilError(t, err)
+	assert.Equal(t, s, table.Strategies[1])
+
+	s, err = table.Interpolate(1)
+	assert.NilError(t, err)
+	assert.Equal(t, s, table.Strategies[1])
+
+	// Halfway, the quorums of both strategies are equally likely.
+	s, err = table.Interpolate(0.5)
+	assert.NilError(t, err)
+
+	for _, n := range []Node{a, b, c} {
+		read := (table.Strategies[0].nodeToReadProbability[n] + table.Strategies[1].nodeToReadProbability[n]) / 2
+		write := (table.Strategies[0].nodeToWriteProbability[n] + table.Strategies[1].nodeToWriteProbability[n]) / 2
+
+		assert.Assert(t, math.Abs(s.nodeToReadProbability[n]-read) < float64EqualityThreshold)
+		assert.Assert(t, math.Abs(s.nodeToWriteProbability[n]-write) < float64EqualityThreshold)
+	}
+
+	_, err = table.Nearest(1.5)
+	assert.Error(t, err, "read fraction 1.5 must be in [0, 1]")
+
+	_, err = StrategyTable{}.Nearest(0.5)
+	assert.Error(t, err, "the strategy table has no read fractions")
+
+	_, err = StrategyTable{}.Interpolate(0.5)
+	assert.Error(t, err, "the strategy table has no read fractions")
+}
@@ -0,0 +1,315 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TraceFormat describes the format of an operation trace.
+type TraceFormat string
+
+const (
+	// CSVTrace is a CSV trace with a header row.
+	CSVTrace TraceFormat = "csv"
+	// JSONLinesTrace is a trace with a JSON object per line.
+	JSONLinesTrace TraceFormat = "jsonl"
+)
+
+// TraceOptions describes how to read an operation trace.
+type TraceOptions struct {
+	// Format defines the format of the trace, CSVTrace by default.
+	Format TraceFormat
+	// Window defines the duration of the time windows, aligned on the Unix epoch.
+	Window time.Duration
+	// TimestampField defines the column (CSV) or key (JSON) of the timestamps, "timestamp" by default. A timestamp is
+	// either RFC 3339 or a number of seconds since the Unix epoch.
+	TimestampField string
+	// OperationField defines the column (CSV) or key (JSON) of the operation types, "op" by default.
+	OperationField string
+	// ReadOperations and WriteOperations define the operation types, case-insensitive, counted as reads and writes,
+	// "read" and "write" by default. The other operations are ignored.
+	ReadOperations  []string
+	WriteOperations []string
+	// Resolution defines the step the read fractions are rounded to, 0.01 by default, so that similar windows share a
+	// read fraction.
+	Resolution float64
+}
+
+// TraceWindow describes the operations of a time window of a trace.
+type TraceWindow struct {
+	Start        time.Time
+	Reads        uint
+	Writes       uint
+	ReadFraction Fraction
+}
+
+// ReadTrace reads an operation trace and returns the Distribution of the read fraction per time window: the weight of
+// a read fraction is the number of windows with that read fraction. The windows without operations are ignored.
+func ReadTrace(r io.Reader, traceOptions TraceOptions) (QuorumDistribution, error) {
+	d, _, err := ReadTraceSeries(r, traceOptions)
+
+	return d, err
+}
+
+// ReadTraceSeries reads an operation trace and returns the Distribution of the read fraction per time window, like
+// ReadTrace, together with the windows sorted by start time.
+func ReadTraceSeries(r io.Reader, traceOptions TraceOptions) (QuorumDistribution, []TraceWindow, error) {
+	options, err := initializeTraceOptions(traceOptions)
+
+	if err != nil {
+		return QuorumDistribution{}, nil, err
+	}
+
+	windows := make(map[int64]*TraceWindow)
+
+	add := func(timestamp string, operation string) error {
+		t, err := parseTimestamp(timestamp)
+
+		if err != nil {
+			return err
+		}
+
+		ns := t.UnixNano()
+		start := ns - ns%int64(options.Window)
+
+		if ns%int64(options.Window) < 0 {
+			start -= int64(options.Window)
+		}
+
+		w, ok := windows[start]
+
+		if !ok {
+			w = &TraceWindow{Start: time.Unix(0, start).UTC()}
+		}
+
+		switch {
+		case containsFold(options.ReadOperations, operation):
+			w.Reads++
+		case containsFold(options.WriteOperations, operation):
+			w.Writes++
+		default:
+			return nil
+		}
+
+		windows[start] = w
+
+		return nil
+	}
+
+	switch options.Format {
+	case CSVTrace:
+		err = readCSVTrace(r, options, add)
+	case JSONLinesTrace:
+		err = readJSONLinesTrace(r, options, add)
+	default:
+		err = fmt.Errorf("unknown trace format %s", options.Format)
+	}
+
+	if err != nil {
+		return QuorumDistribution{}, nil, err
+	}
+
+	if len(windows) == 0 {
+		return QuorumDistribution{}, nil, fmt.Errorf("the trace has no read or write operations")
+	}
+
+	series := make([]TraceWindow, 0, len(windows))
+	values := make(DistributionValues)
+
+	for _, w := range windows {
+		w.ReadFraction = roundFraction(float64(w.Reads)/float64(w.Reads+w.Writes), options.Resolution)
+
+		series = append(series, *w)
+		values[w.ReadFraction]++
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].Start.Before(series[j].Start) })
+
+	d, err := NewHistogramDistribution(values)
+
+	if err != nil {
+		return QuorumDistribution{}, nil, err
+	}
+
+	return d, series, nil
+}
+
+// initializeTraceOptions validates the TraceOptions and sets their default values.
+func initializeTraceOptions(options TraceOptions) (TraceOptions, error) {
+	if options.Window <= 0 {
+		return TraceOptions{}, fmt.Errorf("the window must be > 0")
+	}
+
+	if options.Resolution < 0 || options.Resolution > 1 {
+		return TraceOptions{}, fmt.Errorf("the resolution must be in [0, 1]")
+	}
+
+	if options.Format == "" {
+		options.Format = CSVTrace
+	}
+
+	if options.TimestampField == "" {
+		options.TimestampField = "timestamp"
+	}
+
+	if options.OperationField == "" {
+		options.OperationField = "op"
+	}
+
+	if len(options.ReadOperations) == 0 {
+		options.ReadOperations = []string{"read"}
+	}
+
+	if len(options.WriteOperations) == 0 {
+		options.WriteOperations = []string{"write"}
+	}
+
+	if options.Resolution == 0 {
+		options.Resolution = 0.01
+	}
+
+	return options, nil
+}
+
+// readCSVTrace calls add with the timestamp and the operation of each row of a CSV trace.
+func readCSVTrace(r io.Reader, options TraceOptions, add func(timestamp string, operation string) error) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+
+	if err != nil {
+		return fmt.Errorf("cannot read the trace header: %s", err)
+	}
+
+	timestampColumn, operationColumn := -1, -1
+
+	for i, column := range header {
+		switch strings.TrimSpace(column) {
+		case options.TimestampField:
+			timestampColumn = i
+		case options.OperationField:
+			operationColumn = i
+		}
+	}
+
+	if timestampColumn < 0 {
+		return fmt.Errorf("column %s not found in the trace header", options.TimestampField)
+	}
+
+	if operationColumn < 0 {
+		return fmt.Errorf("column %s not found in the trace header", options.OperationField)
+	}
+
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := add(row[timestampColumn], strings.TrimSpace(row[operationColumn])); err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+	}
+}
+
+// readJSONLinesTrace calls add with the timestamp and the operation of each line of a JSON lines trace.
+func readJSONLinesTrace(r io.Reader, options TraceOptions, add func(timestamp string, operation string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" {
+			continue
+		}
+
+		var record map[string]interface{}
+
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+
+		timestamp, ok := record[options.TimestampField]
+
+		if !ok {
+			return fmt.Errorf("line %d: key %s not found", line, options.TimestampField)
+		}
+
+		operation, ok := record[options.OperationField].(string)
+
+		if !ok {
+			return fmt.Errorf("line %d: key %s not found or not a string", line, options.OperationField)
+		}
+
+		var err error
+
+		switch t := timestamp.(type) {
+		case string:
+			err = add(t, operation)
+		case float64:
+			err = add(strconv.FormatFloat(t, 'f', -1, 64), operation)
+		default:
+			err = fmt.Errorf("the timestamp must be a string or a number")
+		}
+
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseTimestamp parses an RFC 3339 timestamp or a number of seconds since the Unix epoch.
+func parseTimestamp(timestamp string) (time.Time, error) {
+	timestamp = strings.TrimSpace(timestamp)
+
+	if seconds, err := strconv.ParseFloat(timestamp, 64); err == nil {
+		whole, fraction := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(math.Round(fraction*1e9))).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s", timestamp)
+	}
+
+	return t, nil
+}
+
+// roundFraction rounds the read fraction to the nearest multiple of the resolution, within [0, 1].
+func roundFraction(fr Fraction, resolution float64) Fraction {
+	// Dividing by the number of steps, e.g. 75 / 100, avoids the rounding errors of 75 * 0.01.
+	if steps := math.Round(1 / resolution); math.Abs(steps*resolution-1) < 1e-9 {
+		return math.Round(fr*steps) / steps
+	}
+
+	return math.Min(math.Round(fr/resolution)*resolution, 1)
+}
+
+// containsFold returns true if the values contain s, case-insensitively.
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
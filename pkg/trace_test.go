@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTrace(t *testing.T) {
+	csvTrace := `timestamp,op,key
+2021-06-01T10:00:01Z,read,a
+2021-06-01T10:00:20Z,READ,b
+2021-06-01T10:00:30Z,write,a
+2021-06-01T10:00:59Z,read,c
+2021-06-01T10:01:10Z,read,a
+2021-06-01T10:01:11Z,delete,a
+2021-06-01T10:01:12Z,write,b
+2021-06-01T10:03:00Z,read,a
+2021-06-01T10:03:01Z,read,a
+2021-06-01T10:03:02Z,read,a
+2021-06-01T10:03:03Z,write,a
+`
+
+	d, series, err := ReadTraceSeries(strings.NewReader(csvTrace), TraceOptions{Window: time.Minute})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, d.GetValue(), DistributionValues{0.75: 2, 0.5: 1})
+	assert.Equal(t, len(series), 3)
+	assert.Equal(t, series[0].Start, time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, series[0].Reads, uint(3))
+	assert.Equal(t, series[0].Writes, uint(1))
+	assert.Equal(t, series[1].ReadFraction, 0.5)
+	assert.Equal(t, series[2].Start, time.Date(2021, 6, 1, 10, 3, 0, 0, time.UTC))
+
+	// The distribution can be used as the workload of a strategy.
+	a, b := NewNode("a"), NewNode("b")
+	_, err = NewQuorumSystemWithReads(a.Add(b)).Strategy(
+		initializeStrategyOptions(StrategyOptions{Optimize: Load, ReadFraction: d}))
+	assert.NilError(t, err)
+
+	// JSON lines, with Unix timestamps and custom operations.
+	jsonTrace := `{"ts": 1622541600, "kind": "get"}
+{"ts": 1622541601.5, "kind": "put"}
+
+{"ts": "2021-06-01T10:00:02Z", "kind": "put"}
+{"ts": 1622541660, "kind": "get"}
+`
+
+	options := TraceOptions{Format: JSONLinesTrace, Window: time.Minute, TimestampField: "ts", OperationField: "kind",
+		ReadOperations: []string{"get"}, WriteOperations: []string{"put"}, Resolution: 0.1}
+
+	d, err = ReadTrace(strings.NewReader(jsonTrace), options)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, d.GetValue(), DistributionValues{0.3: 1, 1: 1})
+
+	_, err = ReadTrace(strings.NewReader("time,op\n"), TraceOptions{Window: time.Minute})
+	assert.Error(t, err, "column timestamp not found in the trace header")
+
+	_, err = ReadTrace(strings.NewReader("timestamp,op\nyesterday,read\n"), TraceOptions{Window: time.Minute})
+	assert.Error(t, err, "line 2: invalid timestamp yesterday")
+
+	_, err = ReadTrace(strings.NewReader("timestamp,op\n1,delete\n"), TraceOptions{Window: time.Minute})
+	assert.Error(t, err, "the trace has no read or write operations")
+
+	_, err = ReadTrace(strings.NewReader(csvTrace), TraceOptions{})
+	assert.Error(t, err, "the window must be > 0")
+}
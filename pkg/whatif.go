@@ -0,0 +1,252 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+)
+
+// NodeOverride describes the attributes overridden on a node, nil attributes are left unchanged.
+type NodeOverride struct {
+	ReadCapacity  *float64
+	WriteCapacity *float64
+	Latency       *time.Duration
+	LinkCost      *float64
+	ReadCost      *float64
+	WriteCost     *float64
+}
+
+// Metrics describes the metrics of a strategy over a quorum system.
+type Metrics struct {
+	Load        float64
+	Capacity    float64
+	NetworkLoad float64
+	// Latency is in milliseconds.
+	Latency    float64
+	Resilience uint
+}
+
+// Comparison describes the metrics of the optimal strategies of two quorum systems, e.g. before and after a change.
+type Comparison struct {
+	Before         Metrics
+	After          Metrics
+	BeforeStrategy *Strategy
+	AfterStrategy  *Strategy
+}
+
+// WithoutNodes returns a new QuorumSystem where the nodes with the given names are removed, as if they were
+// permanently failed: an Or drops the removed sub-expressions, while an And or a Choose can no longer be satisfied
+// when too few of its sub-expressions are left.
+func (qs QuorumSystem) WithoutNodes(names ...string) (QuorumSystem, error) {
+	removed := make(map[string]bool)
+
+	for _, name := range names {
+		if _, ok := qs.nameToNode[name]; !ok {
+			return QuorumSystem{}, fmt.Errorf("node %s not found", name)
+		}
+		removed[name] = true
+	}
+
+	replace := func(n Node) (Node, bool) {
+		return n, !removed[n.Name]
+	}
+
+	reads, ok := transformExpr(qs.reads, replace)
+
+	if !ok {
+		return QuorumSystem{}, fmt.Errorf("there are no read quorums left without the removed nodes")
+	}
+
+	writes, ok := transformExpr(qs.writes, replace)
+
+	if !ok {
+		return QuorumSystem{}, fmt.Errorf("there are no write quorums left without the removed nodes")
+	}
+
+	return qs.withExprs(reads, writes)
+}
+
+// WithNodeOverrides returns a new QuorumSystem where the capacities, latencies and costs of the nodes are overridden.
+// The overrides are indexed by node name.
+func (qs QuorumSystem) WithNodeOverrides(overrides map[string]NodeOverride) (QuorumSystem, error) {
+	replacements := make(map[Node]Node)
+
+	for name, override := range overrides {
+		n, ok := qs.nameToNode[name]
+
+		if !ok {
+			return QuorumSystem{}, fmt.Errorf("node %s not found", name)
+		}
+
+		replacement := n
+
+		if override.ReadCapacity != nil {
+			readCapacity := *override.ReadCapacity
+			replacement.ReadCapacity = &readCapacity
+		}
+
+		if override.WriteCapacity != nil {
+			writeCapacity := *override.WriteCapacity
+			replacement.WriteCapacity = &writeCapacity
+		}
+
+		if override.Latency != nil {
+			latency := *override.Latency
+			replacement.Latency = &latency
+		}
+
+		if override.LinkCost != nil {
+			linkCost := *override.LinkCost
+			replacement.LinkCost = &linkCost
+		}
+
+		if override.ReadCost != nil {
+			readCost := *override.ReadCost
+			replacement.ReadCost = &readCost
+		}
+
+		if override.WriteCost != nil {
+			writeCost := *override.WriteCost
+			replacement.WriteCost = &writeCost
+		}
+
+		replacements[n] = replacement
+	}
+
+	replace := func(n Node) (Node, bool) {
+		if replacement, ok := replacements[n]; ok {
+			return replacement, true
+		}
+		return n, true
+	}
+
+	reads, _ := transformExpr(qs.reads, replace)
+	writes, _ := transformExpr(qs.writes, replace)
+
+	return qs.withExprs(reads, writes)
+}
+
+// withExprs returns a new QuorumSystem with the given read and write Expr and the payload sizes of qs.
+func (qs QuorumSystem) withExprs(reads Expr, writes Expr) (QuorumSystem, error) {
+	result, err := NewQuorumSystem(reads, writes)
+
+	if err != nil {
+		return QuorumSystem{}, err
+	}
+
+	result.payloadSizes = qs.payloadSizes
+
+	return result, nil
+}
+
+// Compare returns the metrics of the optimal strategy of the quorum system (before) and of the other quorum
+// system (after), given the same StrategyOptions.
+func (qs QuorumSystem) Compare(other QuorumSystem, strategyOptions StrategyOptions) (Comparison, error) {
+	beforeStrategy, err := qs.Strategy(initializeStrategyOptions(strategyOptions))
+
+	if err != nil {
+		return Comparison{}, fmt.Errorf("no strategy before the change: %w", err)
+	}
+
+	afterStrategy, err := other.Strategy(initializeStrategyOptions(strategyOptions))
+
+	if err != nil {
+		return Comparison{}, fmt.Errorf("no strategy after the change: %w", err)
+	}
+
+	before, err := beforeStrategy.metrics(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	after, err := afterStrategy.metrics(&strategyOptions.ReadFraction, &strategyOptions.WriteFraction)
+
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	return Comparison{Before: before, After: after, BeforeStrategy: beforeStrategy, AfterStrategy: afterStrategy}, nil
+}
+
+// metrics returns the Metrics of the strategy given a read and write Distribution.
+func (s Strategy) metrics(rf *Distribution, wf *Distribution) (Metrics, error) {
+	var err error
+
+	m := Metrics{Resilience: s.Qs.Resilience()}
+
+	if m.Load, err = s.Load(rf, wf); err != nil {
+		return Metrics{}, err
+	}
+
+	if m.Capacity, err = s.Capacity(rf, wf); err != nil {
+		return Metrics{}, err
+	}
+
+	if m.NetworkLoad, err = s.NetworkLoad(rf, wf); err != nil {
+		return Metrics{}, err
+	}
+
+	if m.Latency, err = s.Latency(rf, wf); err != nil {
+		return Metrics{}, err
+	}
+
+	return m, nil
+}
+
+// transformExpr returns a copy of the Expr where each node is replaced using replace. When replace returns false
+// the node is removed, and the function returns false if the resulting Expr can no longer be satisfied.
+func transformExpr(e Expr, replace func(n Node) (Node, bool)) (Expr, bool) {
+	switch expr := e.(type) {
+	case Node:
+		return replace(expr)
+	case Or:
+		es := transformExprs(expr.Es, replace)
+
+		if len(es) == 0 {
+			return nil, false
+		}
+
+		if len(es) == 1 {
+			return es[0], true
+		}
+
+		return Or{Es: es}, true
+	case And:
+		es := transformExprs(expr.Es, replace)
+
+		if len(es) < len(expr.Es) {
+			return nil, false
+		}
+
+		if len(es) == 1 {
+			return es[0], true
+		}
+
+		return And{Es: es}, true
+	case Choose:
+		es := transformExprs(expr.Es, replace)
+
+		if len(es) < expr.K {
+			return nil, false
+		}
+
+		choose, err := NewChoose(expr.K, es)
+
+		return choose, err == nil
+	}
+
+	return nil, false
+}
+
+// transformExprs applies transformExpr on a list of Expr, skipping the ones that can no longer be satisfied.
+func transformExprs(es []Expr, replace func(n Node) (Node, bool)) []Expr {
+	result := make([]Expr, 0, len(es))
+
+	for _, e := range es {
+		if t, ok := transformExpr(e, replace); ok {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"gotest.tools/assert"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithoutNodes(t *testing.T) {
+	a, b, c, d, e := NewNode("a"), NewNode("b"), NewNode("c"), NewNode("d"), NewNode("e")
+
+	tests := []struct {
+		reads    Expr
+		writes   Expr
+		removed  []string
+		expected string
+	}{
+		{a.Add(b.Multiply(c)), a.Multiply(b.Add(c)), []string{"b"}, "a"},
+		{a.Add(b.Multiply(c)).Add(d.Multiply(e)), a.Multiply(b.Add(c)).Multiply(d.Add(e)), []string{"b"}, "(a + (d * e))"},
+		{(a.Add(b)).Multiply(c.Add(d)), (a.Multiply(b)).Add(c.Multiply(d)), []string{"a"}, "(b * (c + d))"},
+	}
+
+	for _, tt := range tests {
+		qs, err := NewQuorumSystem(tt.reads, tt.writes)
+		assert.NilError(t, err)
+
+		qs, err = qs.WithoutNodes(tt.removed...)
+		assert.NilError(t, err)
+		assert.Equal(t, qs.reads.String(), tt.expected)
+	}
+
+	qs, err := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d))).WithoutNodes("a")
+	assert.NilError(t, err)
+	assert.Equal(t, qs.reads.String(), "(c * d)")
+	assert.Equal(t, qs.writes.String(), "(b * (c + d))")
+
+	choose, _ := NewChoose(3, []Expr{a, b, c, d, e})
+	qs, err = NewQuorumSystemWithReads(choose).WithoutNodes("e")
+	assert.NilError(t, err)
+
+	// 3 out of the 4 nodes left, for both reads and writes.
+	assert.Assert(t, qs.IsReadQuorum(ExprSet{a: true, b: true, c: true}))
+	assert.Assert(t, !qs.IsReadQuorum(ExprSet{a: true, b: true}))
+	assert.Assert(t, qs.IsWriteQuorum(ExprSet{b: true, c: true, d: true}))
+	assert.Assert(t, !qs.IsWriteQuorum(ExprSet{a: true, b: true, e: true}))
+
+	qs, err = NewQuorumSystemWithReads(choose).WithoutNodes("d", "e")
+	assert.NilError(t, err)
+	assert.Equal(t, qs.reads.String(), "(a * b * c)")
+
+	_, err = NewQuorumSystemWithReads(a.Multiply(b)).WithoutNodes("a")
+	assert.Error(t, err, "there are no read quorums left without the removed nodes")
+
+	// Writing to every node is no longer possible once a node is removed.
+	_, err = NewQuorumSystemWithReads(a.Add(b)).WithoutNodes("a")
+	assert.Error(t, err, "there are no write quorums left without the removed nodes")
+
+	_, err = NewQuorumSystemWithReads(a.Add(b)).WithoutNodes("x")
+	assert.Error(t, err, "node x not found")
+}
+
+func TestCompare(t *testing.T) {
+	const float64EqualityThreshold = 1e-9
+
+	a, b, c, d :=
+		NewNodeWithCapacityAndLatency("a", 2, 1, 1*time.Millisecond), NewNodeWithCapacityAndLatency("b", 2, 1, 2*time.Millisecond),
+		NewNodeWithCapacityAndLatency("c", 2, 1, 3*time.Millisecond), NewNodeWithCapacityAndLatency("d", 2, 1, 4*time.Millisecond)
+
+	qs := NewQuorumSystemWithReads((a.Multiply(b)).Add(c.Multiply(d)))
+
+	strategyOptions := StrategyOptions{
+		Optimize:     Load,
+		ReadFraction: QuorumDistribution{values: DistributionValues{1: 1}},
+	}
+
+	// c loses half of its read capacity.
+	readCapacity := 1.0
+	degraded, err := qs.WithNodeOverrides(map[string]NodeOverride{"c": {ReadCapacity: &readCapacity}})
+	assert.NilError(t, err)
+	assert.Equal(t, *degraded.GetNodeByName("c").ReadCapacity, 1.0)
+	assert.Equal(t, *degraded.GetNodeByName("c").Latency, 3*time.Millisecond)
+	assert.Equal(t, *qs.GetNodeByName("c").ReadCapacity, 2.0)
+
+	comparison, err := qs.Compare(degraded, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(comparison.Before.Load-0.25) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(comparison.After.Load-1.0/3) <= float64EqualityThreshold,
+		fmt.Sprintf("Actual: %f", comparison.After.Load))
+	assert.Assert(t, math.Abs(comparison.After.Capacity-3) <= float64EqualityThreshold)
+	assert.Equal(t, comparison.Before.Resilience, comparison.After.Resilience)
+
+	// a is drained entirely.
+	drained, err := qs.WithoutNodes("a")
+	assert.NilError(t, err)
+
+	comparison, err = qs.Compare(drained, strategyOptions)
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(comparison.After.Load-0.5) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(comparison.After.NetworkLoad-2) <= float64EqualityThreshold)
+	assert.Assert(t, math.Abs(comparison.After.Latency-4) <= float64EqualityThreshold)
+	assert.Equal(t, comparison.Before.Resilience, uint(1))
+	assert.Equal(t, comparison.After.Resilience, uint(0))
+
+	// The errors of the strategies are wrapped.
+	loadLimit := 0.3
+	strategyOptions.Optimize = Latency
+	strategyOptions.LoadLimit = &loadLimit
+
+	_, err = qs.Compare(drained, strategyOptions)
+	assert.Assert(t, errors.Is(err, errNoOptimalStrategy))
+	assert.Assert(t, strings.HasPrefix(err.Error(), "no strategy after the change: "))
+
+	_, err = qs.WithNodeOverrides(map[string]NodeOverride{"x": {}})
+	assert.Error(t, err, "node x not found")
+}